@@ -0,0 +1,331 @@
+package akumu
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// InvalidParam describes a single request parameter
+// that failed to be bound or validated.
+//
+// It's used as the item of the "invalid-params" extension
+// member of a [Problem], as shown in RFC 9457.
+type InvalidParam struct {
+
+	// Name is the name of the parameter, as found in
+	// the request, for example, the query parameter name.
+	Name string `json:"name"`
+
+	// In is the location of the parameter, such as
+	// "path", "query", "header", "cookie" or "form".
+	In string `json:"in,omitempty"`
+
+	// Pointer is a JSON pointer (RFC 6901) to the
+	// parameter in the request body, if it's found there.
+	Pointer string `json:"pointer,omitempty"`
+
+	// Reason is a human-readable explanation
+	// of why the parameter is invalid.
+	Reason string `json:"reason"`
+}
+
+// bindSources are the struct tags that [Bind]
+// understands, in the order they are bound.
+var bindSources = []string{"path", "query", "header", "cookie", "form"}
+
+var (
+	// ErrBindUnsupportedType determines that a struct field
+	// has a type that [Bind] does not know how to convert to.
+	ErrBindUnsupportedType = errors.New("unsupported bind type")
+
+	// ErrBindInvalidParams determines that one or more
+	// parameters could not be bound from the request.
+	ErrBindInvalidParams = errors.New("invalid request parameters")
+
+	// textUnmarshalerType is the [reflect.Type] of [encoding.TextUnmarshaler].
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+	// timeType is the [reflect.Type] of [time.Time].
+	timeType = reflect.TypeFor[time.Time]()
+)
+
+// Bind fills a new `T` struct using values found in the request.
+//
+// Struct fields are bound using the following tags:
+//   - `path:"name"` uses [http.Request.PathValue].
+//   - `query:"name"` uses the URL query values.
+//   - `header:"Name"` uses the request headers.
+//   - `cookie:"name"` uses the request cookies.
+//   - `form:"name"` uses the parsed form values, including multipart forms.
+//
+// Values are converted to the field's type, which can be a string, bool,
+// int, uint, float, [time.Time] (RFC 3339, or the layout given by a
+// `layout:"..."` tag), any [encoding.TextUnmarshaler], a pointer to any
+// of those or a slice of any of those, in which case all the values of
+// the parameter are used.
+//
+// Fields of embedded structs are bound as well. Parameters that are
+// not found in the request leave the field untouched.
+//
+// If any field fails to be bound, a [Problem] with [http.StatusBadRequest]
+// is returned, describing every failed parameter in its "invalid-params"
+// extension member as a list of [InvalidParam].
+func Bind[T any](request *http.Request) (T, error) {
+	result := *new(T)
+
+	if err := bind(request, &result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// bindLookup returns the raw values of the given parameter
+// found in the given source, such as "query" or "form".
+type bindLookup func(source string, name string) ([]string, error)
+
+// bind fills the given struct pointer using the request values.
+//
+// See [Bind] for more information.
+func bind(request *http.Request, target any) error {
+	return bindWith(target, func(source string, name string) ([]string, error) {
+		return bindValues(request, source, name)
+	})
+}
+
+// bindWith fills the given struct pointer using the values
+// returned by the given lookup.
+func bindWith(target any, lookup bindLookup) error {
+	value := reflect.ValueOf(target).Elem()
+
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	params := make([]InvalidParam, 0)
+	errs := make([]error, 0)
+
+	bindStruct(value, lookup, func(source, name string, err error) {
+		params = append(params, InvalidParam{
+			Name:   name,
+			In:     source,
+			Reason: bindReason(err),
+		})
+
+		errs = append(errs, fmt.Errorf("%s %s: %w", source, name, err))
+	})
+
+	if len(params) == 0 {
+		return nil
+	}
+
+	return NewProblem(
+		errors.Join(append([]error{ErrBindInvalidParams}, errs...)...),
+		http.StatusBadRequest,
+	).With("invalid-params", params)
+}
+
+// bindStruct binds every tagged field of the given struct value,
+// calling fail for every parameter that failed to be bound.
+func bindStruct(value reflect.Value, lookup bindLookup, fail func(source, name string, err error)) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			bindStruct(value.Field(i), lookup, fail)
+			continue
+		}
+
+		for _, source := range bindSources {
+			name, ok := field.Tag.Lookup(source)
+
+			if !ok {
+				continue
+			}
+
+			values, err := lookup(source, name)
+
+			if err != nil {
+				fail(source, name, err)
+				continue
+			}
+
+			if len(values) == 0 {
+				continue
+			}
+
+			if err := bindField(value.Field(i), values, field.Tag.Get("layout")); err != nil {
+				fail(source, name, err)
+			}
+		}
+	}
+}
+
+// bindValues returns the raw values of the given parameter
+// found in the given request source.
+func bindValues(request *http.Request, source string, name string) ([]string, error) {
+	switch source {
+	case "path":
+		if value := request.PathValue(name); value != "" {
+			return []string{value}, nil
+		}
+	case "query":
+		return request.URL.Query()[name], nil
+	case "header":
+		return request.Header.Values(name), nil
+	case "cookie":
+		if cookie, err := request.Cookie(name); err == nil {
+			return []string{cookie.Value}, nil
+		}
+	case "form":
+		if err := parseForm(request); err != nil {
+			return nil, err
+		}
+
+		return request.Form[name], nil
+	}
+
+	return nil, nil
+}
+
+// parseForm parses the request form, using a multipart
+// parser when the request has a multipart body.
+func parseForm(request *http.Request) error {
+	if request.Form != nil {
+		return nil
+	}
+
+	media, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+
+	if media == "multipart/form-data" {
+		return request.ParseMultipartForm(32 << 20)
+	}
+
+	return request.ParseForm()
+}
+
+// bindField sets the given values to the field, converting them
+// to the field's type. Slices use all the values while any other
+// type only uses the first one.
+func bindField(field reflect.Value, values []string, layout string) error {
+	if field.Kind() == reflect.Slice && !field.Addr().Type().Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+
+		for i, value := range values {
+			if err := bindScalar(slice.Index(i), value, layout); err != nil {
+				return err
+			}
+		}
+
+		field.Set(slice)
+
+		return nil
+	}
+
+	return bindScalar(field, values[0], layout)
+}
+
+// bindScalar converts the raw string into the field's type and sets it.
+func bindScalar(field reflect.Value, raw string, layout string) error {
+	if field.Kind() == reflect.Pointer {
+		value := reflect.New(field.Type().Elem())
+
+		if err := bindScalar(value.Elem(), raw, layout); err != nil {
+			return err
+		}
+
+		field.Set(value)
+
+		return nil
+	}
+
+	if field.Type() == timeType && layout != "" {
+		parsed, err := time.Parse(layout, raw)
+
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(parsed))
+
+		return nil
+	}
+
+	if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(raw))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(parsed)
+	default:
+		return fmt.Errorf("%w: %s", ErrBindUnsupportedType, field.Type())
+	}
+
+	return nil
+}
+
+// bindReason returns a human-readable reason of the given
+// conversion error, hiding the [strconv] details.
+func bindReason(err error) string {
+	var numErr *strconv.NumError
+
+	if !errors.As(err, &numErr) {
+		return err.Error()
+	}
+
+	kinds := map[string]string{
+		"ParseBool":  "boolean",
+		"ParseInt":   "integer",
+		"ParseUint":  "unsigned integer",
+		"ParseFloat": "number",
+	}
+
+	if errors.Is(numErr.Err, strconv.ErrRange) {
+		return fmt.Sprintf("%q is out of range", numErr.Num)
+	}
+
+	return fmt.Sprintf("%q is not a valid %s", numErr.Num, kinds[numErr.Func])
+}
@@ -0,0 +1,129 @@
+package akumu_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/studiolambda/akumu"
+)
+
+type BindTestPagination struct {
+	Page int `query:"page"`
+}
+
+type BindTestPayload struct {
+	BindTestPagination
+
+	ID      int        `path:"id"`
+	Tags    []string   `query:"tag"`
+	Admin   *bool      `query:"admin"`
+	Since   time.Time  `query:"since"`
+	Day     time.Time  `query:"day" layout:"2006-01-02"`
+	Tenant  string     `header:"X-Tenant"`
+	Session string     `cookie:"session"`
+	Name    string     `form:"name"`
+	Address netip.Addr `query:"address"`
+}
+
+func TestBind(t *testing.T) {
+	var payload BindTestPayload
+
+	router := akumu.NewRouter()
+	router.Post("/users/{id}", func(request *http.Request) error {
+		bound, err := akumu.Bind[BindTestPayload](request)
+
+		if err != nil {
+			return err
+		}
+
+		payload = bound
+
+		return akumu.Response(http.StatusOK)
+	})
+
+	query := "page=3&tag=a&tag=b&admin=true&since=2024-01-02T03:04:05Z&day=2024-05-06&address=127.0.0.1"
+	request, err := http.NewRequest(http.MethodPost, "/users/7?"+query, strings.NewReader("name=john"))
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("X-Tenant", "acme")
+	request.AddCookie(&http.Cookie{Name: "session", Value: "secret"})
+
+	response := router.Record(request)
+
+	if expected := http.StatusOK; response.Code != expected {
+		t.Fatalf("expected status code %d but got %d: %s", expected, response.Code, response.Body)
+	}
+
+	if payload.ID != 7 || payload.Page != 3 || payload.Tenant != "acme" || payload.Session != "secret" || payload.Name != "john" {
+		t.Fatalf("unexpected bound payload: %+v", payload)
+	}
+
+	if len(payload.Tags) != 2 || payload.Tags[0] != "a" || payload.Tags[1] != "b" {
+		t.Fatalf("unexpected bound tags: %v", payload.Tags)
+	}
+
+	if payload.Admin == nil || !*payload.Admin {
+		t.Fatalf("unexpected bound admin: %v", payload.Admin)
+	}
+
+	if expected := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC); !payload.Since.Equal(expected) {
+		t.Fatalf("expected since %s but got %s", expected, payload.Since)
+	}
+
+	if expected := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC); !payload.Day.Equal(expected) {
+		t.Fatalf("expected day %s but got %s", expected, payload.Day)
+	}
+
+	if expected := netip.MustParseAddr("127.0.0.1"); payload.Address != expected {
+		t.Fatalf("expected address %s but got %s", expected, payload.Address)
+	}
+}
+
+func TestBindInvalidParams(t *testing.T) {
+	router := akumu.NewRouter()
+	router.Get("/users/{id}", func(request *http.Request) error {
+		if _, err := akumu.Bind[BindTestPayload](request); err != nil {
+			return err
+		}
+
+		return akumu.Response(http.StatusOK)
+	})
+
+	request, err := http.NewRequest(http.MethodGet, "/users/foo?page=bar&since=yesterday", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request.Header.Set("Accept", "application/problem+json")
+
+	response := router.Record(request)
+
+	if expected := http.StatusBadRequest; response.Code != expected {
+		t.Fatalf("expected status code %d but got %d", expected, response.Code)
+	}
+
+	var body struct {
+		InvalidParams []akumu.InvalidParam `json:"invalid-params"`
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if expected := 3; len(body.InvalidParams) != expected {
+		t.Fatalf("expected %d invalid params but got %d", expected, len(body.InvalidParams))
+	}
+
+	if expected := (akumu.InvalidParam{Name: "page", In: "query", Reason: `"bar" is not a valid integer`}); body.InvalidParams[0] != expected {
+		t.Fatalf("expected invalid param %+v but got %+v", expected, body.InvalidParams[0])
+	}
+}
@@ -90,6 +90,25 @@ type Builder struct {
 	//
 	// The handler determines what to do with it.
 	writer func(writer http.ResponseWriter)
+
+	// negotiable is the value that's encoded once the request is
+	// known, picking the representation of the response based on
+	// the request's Accept header.
+	//
+	// The [DefaultResponderHandler] encodes it right after handling
+	// errors, given there's also the body, err, stream and writer possibilities.
+	//
+	// The handler determines what to do with it.
+	negotiable any
+
+	// negotiates determines if the negotiable value is set, as
+	// a nil value is still encoded in the negotiated representation.
+	negotiates bool
+
+	// redacted stores the [ErrRedacted] of the [Problem] this builder
+	// responds with, if it has been redacted. It's reported instead
+	// of the [ErrServer] when the response is a server error.
+	redacted *ErrRedacted
 }
 
 // RawBuilder is a raw response that can be used
@@ -104,6 +123,11 @@ var (
 	// changes to the [http.ResponseWriter]. This should most likely
 	// not happen due [http.ResponseWriter] already implementing [http.Flusher].
 	ErrWriterRequiresFlusher = errors.New("response writer requires a flusher")
+
+	// ErrNotAcceptable is an error that determines that the
+	// response cannot be encoded into any media type that
+	// the request accepts.
+	ErrNotAcceptable = errors.New("no acceptable response media type")
 )
 
 // Error implements the error interface
@@ -135,20 +159,22 @@ func writeHeaders(writer http.ResponseWriter, builder Builder) bool {
 // for akumu to handle http responses, although it can be customized
 // if needed.
 //
-// By default, this handler does make use of the [OnErrorHook] that is
-// found in the request's context, on the [OnErrorKey] key if server error happens.
+// By default, this handler does run the [OnErrorHook] subscribers of the
+// [Hooks] found in the request's context, on the [HooksKey] key, if a server
+// error happens, and the [OnStreamClosedHook] subscribers once a stream closes.
 //
 // By default, this handler does handle the [Builder] in the following order of priority:
 //  1. errors
-//  2. writer
-//  3. body
-//  4. stream
-//  5. default (no body)
+//  2. negotiation
+//  3. writer
+//  4. body
+//  5. stream
+//  6. default (no body)
 //
 // This means that if a [Builder] contain more than one possible response type, only the
 // first one defined, following the order above, will be executed.
 func DefaultResponderHandler(writer http.ResponseWriter, request *http.Request, builder Builder) {
-	onError, hasOnError := request.Context().Value(OnErrorKey{}).(OnErrorHook)
+	hooks := RequestHooks(request)
 
 	if builder.err != nil {
 		parent := builder.WithoutError()
@@ -157,14 +183,15 @@ func DefaultResponderHandler(writer http.ResponseWriter, request *http.Request,
 		return
 	}
 
-	if builder.writer != nil {
-		if writeHeaders(writer, builder) && hasOnError {
-			serverErr := ErrServer{
-				Code:    builder.status,
-				Request: request,
-			}
+	if builder.negotiates {
+		builder.negotiated(request).Handle(writer, request)
+
+		return
+	}
 
-			onError(errors.Join(serverErr, ErrServerWriter))
+	if builder.writer != nil {
+		if writeHeaders(writer, builder) {
+			builder.reportServerError(request, ErrServerWriter)
 		}
 
 		builder.writer(writer)
@@ -182,13 +209,8 @@ func DefaultResponderHandler(writer http.ResponseWriter, request *http.Request,
 			return
 		}
 
-		if writeHeaders(writer, builder) && hasOnError {
-			serverErr := ErrServer{
-				Code:    builder.status,
-				Request: request,
-			}
-
-			onError(errors.Join(serverErr, ErrServerBody))
+		if writeHeaders(writer, builder) {
+			builder.reportServerError(request, ErrServerBody)
 		}
 
 		writer.Write(body)
@@ -207,13 +229,8 @@ func DefaultResponderHandler(writer http.ResponseWriter, request *http.Request,
 			return
 		}
 
-		if writeHeaders(writer, builder) && hasOnError {
-			serverErr := ErrServer{
-				Code:    builder.status,
-				Request: request,
-			}
-
-			onError(errors.Join(serverErr, ErrServerStream))
+		if writeHeaders(writer, builder) {
+			builder.reportServerError(request, ErrServerStream)
 		}
 
 		flusher.Flush()
@@ -221,9 +238,13 @@ func DefaultResponderHandler(writer http.ResponseWriter, request *http.Request,
 		for {
 			select {
 			case <-request.Context().Done():
+				hooks.RunStreamClosed(request, request.Context().Err())
+
 				return
 			case message, ok := <-builder.stream:
 				if !ok {
+					hooks.RunStreamClosed(request, nil)
+
 					return
 				}
 
@@ -234,14 +255,27 @@ func DefaultResponderHandler(writer http.ResponseWriter, request *http.Request,
 		}
 	}
 
-	if writeHeaders(writer, builder) && hasOnError {
-		serverErr := ErrServer{
-			Code:    builder.status,
-			Request: request,
-		}
+	if writeHeaders(writer, builder) {
+		builder.reportServerError(request, ErrServerDefault)
+	}
+}
+
+// reportServerError runs the [OnErrorHook] subscribers of the request
+// once for the server error response, giving them the [ErrRedacted] of
+// the builder, if any, or the [ErrServer] joined with the given kind.
+func (builder Builder) reportServerError(request *http.Request, kind error) {
+	if builder.redacted != nil {
+		builder.redacted.report(request)
+
+		return
+	}
 
-		onError(errors.Join(serverErr, ErrServerDefault))
+	serverErr := ErrServer{
+		Code:    builder.status,
+		Request: request,
 	}
+
+	RequestHooks(request).RunError(errors.Join(serverErr, kind))
 }
 
 // Raw is a used to create a [RawBuilder] response
@@ -451,6 +485,95 @@ func (builder Builder) JSON(body any) Builder {
 		BodyReader(buffer)
 }
 
+// Encode encodes the given body variable into the
+// request's body using the [Codec] registered for the
+// given media type and also makes sure the Content-Type
+// is set to that media type.
+//
+// If there's no [Codec] registered or the encoding fails,
+// the [Builder] is set to a status of [http.StatusInternalServerError]
+// with the failed error.
+func (builder Builder) Encode(media string, body any) Builder {
+	codec, ok := LookupCodec(media)
+
+	if !ok {
+		return builder.
+			Status(http.StatusInternalServerError).
+			Failed(fmt.Errorf("%w: %q", ErrCodecNotFound, media))
+	}
+
+	buffer := &bytes.Buffer{}
+
+	if err := codec.Encode(buffer, body); err != nil {
+		return builder.
+			Status(http.StatusInternalServerError).
+			Failed(err)
+	}
+
+	return builder.
+		Header("Content-Type", media).
+		BodyReader(buffer)
+}
+
+// Negotiate encodes the given body variable into the
+// request's body using the registered [Codec] that best
+// matches the request's Accept header, and also makes sure
+// the Content-Type is set to that codec's media type and
+// that "Accept" is appended to the Vary header.
+//
+// The [Codec] is picked when the response is handled, as it
+// depends on the request. Requests without an Accept header
+// use the first registered [Codec].
+//
+// Codecs that cannot encode the value, such as XML for maps,
+// are skipped in favour of the next acceptable one. If none of
+// the acceptable codecs can encode it, the response is a [Problem]
+// with [http.StatusNotAcceptable] and the [ErrNotAcceptable] error,
+// which lists the media types that are supported in the
+// "supported" extension member.
+func (builder Builder) Negotiate(body any) Builder {
+	builder.negotiable = body
+	builder.negotiates = true
+
+	return builder.AppendHeader("Vary", "Accept")
+}
+
+// negotiated returns the [Builder] with its negotiable value
+// encoded using the registered [Codec] that best matches the
+// given request's Accept header and is able to encode it.
+func (builder Builder) negotiated(request *http.Request) Builder {
+	body := builder.negotiable
+	builder.negotiable = nil
+	builder.negotiates = false
+
+	for _, codec := range negotiateCodecs(request) {
+		buffer := &bytes.Buffer{}
+
+		if err := codec.Encode(buffer, body); codecUnsupported(err) {
+			continue
+		} else if err != nil {
+			return builder.
+				Status(http.StatusInternalServerError).
+				Failed(err)
+		}
+
+		return builder.
+			Header("Content-Type", codec.MediaType()).
+			BodyReader(buffer)
+	}
+
+	supported := make([]string, 0)
+
+	for _, codec := range Codecs() {
+		supported = append(supported, codec.MediaType())
+	}
+
+	return builder.Failed(
+		NewProblem(ErrNotAcceptable, http.StatusNotAcceptable).
+			With("supported", supported),
+	)
+}
+
 // BodyWriter marks the [Builder] as a custom writer function
 // making the handler execute the logic passed here when a response
 // is written.
@@ -516,9 +639,22 @@ func (builder Builder) Merge(other Builder) Builder {
 		builder.stream = other.stream
 	}
 
+	if other.writer != nil {
+		builder.writer = other.writer
+	}
+
 	if other.err != nil {
 		builder.err = other.err
 	}
 
+	if other.negotiates {
+		builder.negotiable = other.negotiable
+		builder.negotiates = true
+	}
+
+	if other.redacted != nil {
+		builder.redacted = other.redacted
+	}
+
 	return builder
 }
@@ -0,0 +1,407 @@
+package akumu
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/studiolambda/akumu/utils"
+)
+
+// Codec defines how values are encoded into and
+// decoded from a specific media type.
+//
+// Codecs are registered using [RegisterCodec] and
+// are used by methods such as [Builder.Encode].
+type Codec interface {
+
+	// MediaType returns the media type that the
+	// codec encodes to and decodes from.
+	MediaType() string
+
+	// Encode writes the encoded value into the writer.
+	Encode(writer io.Writer, value any) error
+
+	// Decode reads the reader and decodes it into the value,
+	// that must be a pointer.
+	Decode(reader io.Reader, value any) error
+}
+
+var (
+	// ErrCodecNotFound determines that there's no
+	// [Codec] registered for a given media type.
+	ErrCodecNotFound = errors.New("codec not found")
+
+	// ErrCodecUnsupportedValue determines that a [Codec]
+	// does not know how to encode or decode a given value.
+	ErrCodecUnsupportedValue = errors.New("codec does not support the value")
+)
+
+// codecs stores the registered [Codec] in
+// registration order, keyed by media type.
+var codecs = struct {
+	sync.RWMutex
+	list []Codec
+}{
+	list: []Codec{
+		JSONCodec{},
+		XMLCodec{},
+		CBORCodec{},
+		FormCodec{},
+	},
+}
+
+// RegisterCodec registers the given [Codec], replacing
+// any [Codec] previously registered with the same media type.
+//
+// The following codecs are registered by default:
+//   - [JSONCodec]
+//   - [XMLCodec]
+//   - [CBORCodec]
+//   - [FormCodec]
+func RegisterCodec(codec Codec) {
+	codecs.Lock()
+	defer codecs.Unlock()
+
+	index := slices.IndexFunc(codecs.list, func(other Codec) bool {
+		return other.MediaType() == codec.MediaType()
+	})
+
+	if index >= 0 {
+		codecs.list[index] = codec
+
+		return
+	}
+
+	codecs.list = append(codecs.list, codec)
+}
+
+// LookupCodec returns the [Codec] registered with the given media type.
+// The second return value determines if the [Codec] was found or not.
+func LookupCodec(media string) (Codec, bool) {
+	codecs.RLock()
+	defer codecs.RUnlock()
+
+	for _, codec := range codecs.list {
+		if codec.MediaType() == media {
+			return codec, true
+		}
+	}
+
+	return nil, false
+}
+
+// Codecs returns all the registered [Codec] in registration order.
+func Codecs() []Codec {
+	codecs.RLock()
+	defer codecs.RUnlock()
+
+	return slices.Clone(codecs.list)
+}
+
+// Decode decodes the request body into `T` using the [Codec]
+// registered for the request's Content-Type.
+//
+// If no [Codec] is found, a [Problem] with [http.StatusUnsupportedMediaType]
+// is returned, while decoding errors return a [Problem] with
+// [http.StatusBadRequest]. For JSON bodies, consider using [JSON] instead.
+func Decode[T any](request *http.Request) (T, error) {
+	result := *new(T)
+	media, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	codec, ok := LookupCodec(media)
+
+	if !ok {
+		return result, NewProblem(
+			fmt.Errorf("%w: %q", ErrCodecNotFound, media),
+			http.StatusUnsupportedMediaType,
+		)
+	}
+
+	if request.Body == nil {
+		return result, NewProblem(io.EOF, http.StatusBadRequest)
+	}
+
+	if err := codec.Decode(request.Body, &result); err != nil {
+		return result, NewProblem(err, http.StatusBadRequest)
+	}
+
+	return result, nil
+}
+
+// negotiateCodecs returns the registered [Codec] that match the
+// Accept header of the given request, from the best match to the
+// worst one. Codecs are offered in registration order, so requests
+// without an Accept header prefer the first registered [Codec].
+func negotiateCodecs(request *http.Request) []Codec {
+	available := Codecs()
+	offers := make([]string, len(available))
+
+	for i, codec := range available {
+		offers[i] = codec.MediaType()
+	}
+
+	accept := utils.ParseAccept(request)
+	negotiated := make([]Codec, 0, len(available))
+
+	for len(offers) > 0 {
+		best := accept.Best(offers...)
+		index := slices.Index(offers, best)
+
+		if best == "" || index < 0 {
+			break
+		}
+
+		negotiated = append(negotiated, available[index])
+		available = slices.Delete(available, index, index+1)
+		offers = slices.Delete(offers, index, index+1)
+	}
+
+	return negotiated
+}
+
+// codecUnsupported reports whether the given encoding error
+// means that the [Codec] cannot represent the value, so that
+// another [Codec] may be used instead.
+func codecUnsupported(err error) bool {
+	var unsupported *xml.UnsupportedTypeError
+
+	return errors.Is(err, ErrCodecUnsupportedValue) || errors.As(err, &unsupported)
+}
+
+// JSONCodec is the [Codec] of the "application/json" media type.
+type JSONCodec struct{}
+
+// MediaType implements [Codec].
+func (JSONCodec) MediaType() string {
+	return "application/json"
+}
+
+// Encode implements [Codec] using [json.Encoder].
+func (JSONCodec) Encode(writer io.Writer, value any) error {
+	return json.NewEncoder(writer).Encode(value)
+}
+
+// Decode implements [Codec] using [json.Decoder].
+func (JSONCodec) Decode(reader io.Reader, value any) error {
+	return json.NewDecoder(reader).Decode(value)
+}
+
+// XMLCodec is the [Codec] of the "application/xml" media type.
+type XMLCodec struct{}
+
+// MediaType implements [Codec].
+func (XMLCodec) MediaType() string {
+	return "application/xml"
+}
+
+// Encode implements [Codec] using [xml.Encoder], including the XML header.
+func (XMLCodec) Encode(writer io.Writer, value any) error {
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+
+	return xml.NewEncoder(writer).Encode(value)
+}
+
+// Decode implements [Codec] using [xml.Decoder].
+func (XMLCodec) Decode(reader io.Reader, value any) error {
+	return xml.NewDecoder(reader).Decode(value)
+}
+
+// FormCodec is the [Codec] of the "application/x-www-form-urlencoded"
+// media type.
+//
+// It encodes and decodes [url.Values], map[string]string, map[string][]string
+// and structs with `form:"name"` tags, the same way [Bind] does.
+type FormCodec struct{}
+
+// MediaType implements [Codec].
+func (FormCodec) MediaType() string {
+	return "application/x-www-form-urlencoded"
+}
+
+// Encode implements [Codec].
+func (FormCodec) Encode(writer io.Writer, value any) error {
+	values := make(url.Values)
+
+	switch typed := value.(type) {
+	case url.Values:
+		values = typed
+	case map[string][]string:
+		values = typed
+	case map[string]string:
+		for key, value := range typed {
+			values.Set(key, value)
+		}
+	default:
+		reflected := reflect.Indirect(reflect.ValueOf(value))
+
+		if reflected.Kind() != reflect.Struct {
+			return fmt.Errorf("%w: %T", ErrCodecUnsupportedValue, value)
+		}
+
+		formValues(reflected, values)
+	}
+
+	_, err := io.WriteString(writer, values.Encode())
+
+	return err
+}
+
+// Decode implements [Codec].
+func (FormCodec) Decode(reader io.Reader, value any) error {
+	body, err := io.ReadAll(reader)
+
+	if err != nil {
+		return err
+	}
+
+	values, err := url.ParseQuery(string(body))
+
+	if err != nil {
+		return err
+	}
+
+	switch typed := value.(type) {
+	case *url.Values:
+		*typed = values
+	case *map[string][]string:
+		*typed = values
+	case *map[string]string:
+		*typed = make(map[string]string, len(values))
+
+		for key := range values {
+			(*typed)[key] = values.Get(key)
+		}
+	default:
+		if reflected := reflect.ValueOf(value); reflected.Kind() != reflect.Pointer || reflected.Elem().Kind() != reflect.Struct {
+			return fmt.Errorf("%w: %T", ErrCodecUnsupportedValue, value)
+		}
+
+		return bindWith(value, func(source string, name string) ([]string, error) {
+			if source == "form" {
+				return values[name], nil
+			}
+
+			return nil, nil
+		})
+	}
+
+	return nil
+}
+
+// formValues adds the `form` tagged fields of the given
+// struct value into the given values.
+func formValues(value reflect.Value, values url.Values) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			formValues(value.Field(i), values)
+			continue
+		}
+
+		name, ok := field.Tag.Lookup("form")
+
+		if !ok {
+			continue
+		}
+
+		current := value.Field(i)
+
+		if current.Kind() == reflect.Pointer {
+			if current.IsNil() {
+				continue
+			}
+
+			current = current.Elem()
+		}
+
+		if current.Kind() == reflect.Slice && current.Type().Elem().Kind() != reflect.Uint8 {
+			for j := 0; j < current.Len(); j++ {
+				values.Add(name, formValue(current.Index(j)))
+			}
+
+			continue
+		}
+
+		values.Add(name, formValue(current))
+	}
+}
+
+// formValue returns the string representation of the given value.
+func formValue(value reflect.Value) string {
+	if marshaler, ok := value.Interface().(interface{ MarshalText() ([]byte, error) }); ok {
+		if text, err := marshaler.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	return fmt.Sprint(value.Interface())
+}
+
+// codecValue transforms the given value into a generic representation
+// made of nil, bool, int64, uint64, float64, string, []any and map[string]any
+// by using its JSON representation, so that [json.Marshaler] values
+// are respected by representations other than JSON, such as XML.
+func codecValue(value any) (any, error) {
+	encoded, err := json.Marshal(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var generic any
+
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	return codecNumbers(generic), nil
+}
+
+// codecNumbers replaces every [json.Number] with an int64,
+// uint64 or float64, in that order of preference.
+func codecNumbers(value any) any {
+	switch typed := value.(type) {
+	case json.Number:
+		if number, err := strconv.ParseInt(typed.String(), 10, 64); err == nil {
+			return number
+		}
+
+		if number, err := strconv.ParseUint(typed.String(), 10, 64); err == nil {
+			return number
+		}
+
+		number, _ := typed.Float64()
+
+		return number
+	case []any:
+		for i, item := range typed {
+			typed[i] = codecNumbers(item)
+		}
+	case map[string]any:
+		for key, item := range typed {
+			typed[key] = codecNumbers(item)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,1025 @@
+package akumu
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CBORCodec is the [Codec] of the "application/cbor" media type,
+// as defined in RFC 8949.
+//
+// Values are encoded using reflection, following the same rules as the
+// [json] package: `json` tags are respected and structs are encoded as
+// maps keyed by their field names. Byte slices are encoded as byte strings
+// and floating point numbers keep their precision, including NaN and
+// infinities. Maps are encoded with their keys sorted, as described in
+// RFC 8949's core deterministic encoding requirements.
+//
+// Types implementing [encoding.TextMarshaler] are encoded as text strings,
+// while types implementing [json.Marshaler], such as [Problem], are encoded
+// from their JSON representation. Decoding supports any well-formed CBOR
+// item, ignoring tags, and follows the same rules in reverse.
+type CBORCodec struct{}
+
+// cborMaxDepth is the maximum nesting of CBOR
+// arrays and maps that is encoded or decoded.
+const cborMaxDepth = 512
+
+var (
+	// ErrCBORMalformed determines that a CBOR
+	// payload is not well-formed.
+	ErrCBORMalformed = errors.New("malformed cbor")
+)
+
+var (
+	// jsonMarshalerType is the [reflect.Type] of [json.Marshaler].
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+
+	// jsonUnmarshalerType is the [reflect.Type] of [json.Unmarshaler].
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+
+	// textMarshalerType is the [reflect.Type] of [encoding.TextMarshaler].
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// cborField is a struct field that's encoded
+// and decoded by the [CBORCodec].
+type cborField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	omitZero  bool
+}
+
+// cborFieldsCache caches the fields of the
+// struct types, keyed by [reflect.Type].
+var cborFieldsCache sync.Map
+
+// MediaType implements [Codec].
+func (CBORCodec) MediaType() string {
+	return "application/cbor"
+}
+
+// Encode implements [Codec].
+func (CBORCodec) Encode(writer io.Writer, value any) error {
+	buffer := &bytes.Buffer{}
+
+	if err := cborEncode(buffer, reflect.ValueOf(value), 0); err != nil {
+		return err
+	}
+
+	_, err := buffer.WriteTo(writer)
+
+	return err
+}
+
+// Decode implements [Codec].
+func (CBORCodec) Decode(reader io.Reader, value any) error {
+	generic, err := cborDecode(bufio.NewReader(reader), 0)
+
+	if err != nil {
+		return err
+	}
+
+	target := reflect.ValueOf(value)
+
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("%w: %T", ErrCodecUnsupportedValue, value)
+	}
+
+	return cborAssign(generic, target.Elem())
+}
+
+// cborFields returns the fields of the given struct type, following
+// the same rules as the [json] package does, including the fields
+// promoted from embedded structs.
+func cborFields(typ reflect.Type) []cborField {
+	if cached, ok := cborFieldsCache.Load(typ); ok {
+		return cached.([]cborField)
+	}
+
+	type candidate struct {
+		cborField
+		depth  int
+		tagged bool
+	}
+
+	candidates := make([]candidate, 0, typ.NumField())
+
+	var collect func(typ reflect.Type, index []int, depth int)
+
+	collect = func(typ reflect.Type, index []int, depth int) {
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			tag := field.Tag.Get("json")
+
+			if tag == "-" {
+				continue
+			}
+
+			name, options, _ := strings.Cut(tag, ",")
+			fieldIndex := append(slices.Clone(index), i)
+
+			if field.Anonymous && name == "" {
+				embedded := field.Type
+
+				if embedded.Kind() == reflect.Pointer {
+					embedded = embedded.Elem()
+				}
+
+				if embedded.Kind() == reflect.Struct && depth < cborMaxDepth {
+					collect(embedded, fieldIndex, depth+1)
+
+					continue
+				}
+			}
+
+			if !field.IsExported() {
+				continue
+			}
+
+			candidates = append(candidates, candidate{
+				cborField: cborField{
+					name:      cborName(name, field.Name),
+					index:     fieldIndex,
+					omitEmpty: slices.Contains(strings.Split(options, ","), "omitempty"),
+					omitZero:  slices.Contains(strings.Split(options, ","), "omitzero"),
+				},
+				depth:  depth,
+				tagged: name != "",
+			})
+		}
+	}
+
+	collect(typ, nil, 0)
+
+	fields := make([]cborField, 0, len(candidates))
+
+	for _, current := range candidates {
+		dominant := true
+		conflicts := 0
+
+		for _, other := range candidates {
+			if other.name != current.name {
+				continue
+			}
+
+			if other.depth < current.depth || (other.depth == current.depth && other.tagged && !current.tagged) {
+				dominant = false
+			}
+
+			if other.depth == current.depth && other.tagged == current.tagged {
+				conflicts++
+			}
+		}
+
+		// Fields with the same name at the same depth
+		// cancel each other out, as the [json] package does.
+		if dominant && conflicts == 1 {
+			fields = append(fields, current.cborField)
+		}
+	}
+
+	cached, _ := cborFieldsCache.LoadOrStore(typ, fields)
+
+	return cached.([]cborField)
+}
+
+// cborName returns the encoded name of a field, which is
+// its tagged name if any or its Go name otherwise.
+func cborName(tagged string, name string) string {
+	if tagged != "" {
+		return tagged
+	}
+
+	return name
+}
+
+// cborFieldValue returns the value of the field at the given index,
+// allocating the nil embedded struct pointers if `allocate` is true.
+// The second return value determines if the field can be reached.
+func cborFieldValue(value reflect.Value, index []int, allocate bool) (reflect.Value, bool) {
+	for i, position := range index {
+		if i > 0 && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				if !allocate || !value.CanSet() {
+					return reflect.Value{}, false
+				}
+
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+
+			value = value.Elem()
+		}
+
+		value = value.Field(position)
+	}
+
+	return value, true
+}
+
+// cborOmit reports if the given field value is
+// omitted due to its `omitempty` or `omitzero` options.
+func cborOmit(field cborField, value reflect.Value) bool {
+	if field.omitZero && value.IsZero() {
+		return true
+	}
+
+	if !field.omitEmpty {
+		return false
+	}
+
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return value.IsZero()
+	}
+
+	return false
+}
+
+// cborHeader writes the initial bytes of a CBOR item
+// with the given major type and argument.
+func cborHeader(buffer *bytes.Buffer, major byte, argument uint64) {
+	major <<= 5
+
+	switch {
+	case argument < 24:
+		buffer.WriteByte(major | byte(argument))
+	case argument <= math.MaxUint8:
+		buffer.Write([]byte{major | 24, byte(argument)})
+	case argument <= math.MaxUint16:
+		buffer.WriteByte(major | 25)
+		buffer.Write(binary.BigEndian.AppendUint16(nil, uint16(argument)))
+	case argument <= math.MaxUint32:
+		buffer.WriteByte(major | 26)
+		buffer.Write(binary.BigEndian.AppendUint32(nil, uint32(argument)))
+	default:
+		buffer.WriteByte(major | 27)
+		buffer.Write(binary.BigEndian.AppendUint64(nil, argument))
+	}
+}
+
+// cborInteger writes the given signed integer.
+func cborInteger(buffer *bytes.Buffer, value int64) {
+	if value < 0 {
+		cborHeader(buffer, 1, uint64(-(value + 1)))
+
+		return
+	}
+
+	cborHeader(buffer, 0, uint64(value))
+}
+
+// cborEncode writes the given value as a CBOR item.
+func cborEncode(buffer *bytes.Buffer, value reflect.Value, depth int) error {
+	if depth > cborMaxDepth {
+		return fmt.Errorf("%w: maximum depth exceeded", ErrCodecUnsupportedValue)
+	}
+
+	if !value.IsValid() {
+		buffer.WriteByte(0xf6)
+
+		return nil
+	}
+
+	if value.Kind() != reflect.Pointer && value.CanAddr() && reflect.PointerTo(value.Type()).Implements(jsonMarshalerType) {
+		value = value.Addr()
+	}
+
+	if value.Type().Implements(jsonMarshalerType) {
+		if value.Kind() == reflect.Pointer && value.IsNil() {
+			buffer.WriteByte(0xf6)
+
+			return nil
+		}
+
+		generic, err := codecValue(value.Interface())
+
+		if err != nil {
+			return err
+		}
+
+		return cborEncode(buffer, reflect.ValueOf(generic), depth+1)
+	}
+
+	if value.Type().Implements(textMarshalerType) {
+		if value.Kind() == reflect.Pointer && value.IsNil() {
+			buffer.WriteByte(0xf6)
+
+			return nil
+		}
+
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+
+		if err != nil {
+			return err
+		}
+
+		cborHeader(buffer, 3, uint64(len(text)))
+		buffer.Write(text)
+
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			buffer.WriteByte(0xf6)
+
+			return nil
+		}
+
+		return cborEncode(buffer, value.Elem(), depth+1)
+	case reflect.Bool:
+		if value.Bool() {
+			buffer.WriteByte(0xf5)
+
+			return nil
+		}
+
+		buffer.WriteByte(0xf4)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		cborInteger(buffer, value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		cborHeader(buffer, 0, value.Uint())
+	case reflect.Float32:
+		buffer.WriteByte(0xfa)
+		buffer.Write(binary.BigEndian.AppendUint32(nil, math.Float32bits(float32(value.Float()))))
+	case reflect.Float64:
+		buffer.WriteByte(0xfb)
+		buffer.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(value.Float())))
+	case reflect.String:
+		cborHeader(buffer, 3, uint64(value.Len()))
+		buffer.WriteString(value.String())
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			buffer.WriteByte(0xf6)
+
+			return nil
+		}
+
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			data := make([]byte, value.Len())
+			reflect.Copy(reflect.ValueOf(data), value)
+			cborHeader(buffer, 2, uint64(len(data)))
+			buffer.Write(data)
+
+			return nil
+		}
+
+		cborHeader(buffer, 4, uint64(value.Len()))
+
+		for i := 0; i < value.Len(); i++ {
+			if err := cborEncode(buffer, value.Index(i), depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if value.IsNil() {
+			buffer.WriteByte(0xf6)
+
+			return nil
+		}
+
+		return cborMap(buffer, value, depth)
+	case reflect.Struct:
+		fields := cborFields(value.Type())
+		values := make([]reflect.Value, 0, len(fields))
+		names := make([]string, 0, len(fields))
+
+		for _, field := range fields {
+			current, ok := cborFieldValue(value, field.index, false)
+
+			if !ok || cborOmit(field, current) {
+				continue
+			}
+
+			values = append(values, current)
+			names = append(names, field.name)
+		}
+
+		cborHeader(buffer, 5, uint64(len(values)))
+
+		for i, current := range values {
+			cborHeader(buffer, 3, uint64(len(names[i])))
+			buffer.WriteString(names[i])
+
+			if err := cborEncode(buffer, current, depth+1); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrCodecUnsupportedValue, value.Type())
+	}
+
+	return nil
+}
+
+// cborMap writes the given map value, with its encoded
+// keys sorted in bytewise lexicographic order.
+func cborMap(buffer *bytes.Buffer, value reflect.Value, depth int) error {
+	type entry struct {
+		key   []byte
+		value reflect.Value
+	}
+
+	entries := make([]entry, 0, value.Len())
+	iterator := value.MapRange()
+
+	for iterator.Next() {
+		key := &bytes.Buffer{}
+		current := iterator.Key()
+
+		switch {
+		case current.Kind() == reflect.String:
+			cborHeader(key, 3, uint64(current.Len()))
+			key.WriteString(current.String())
+		case current.Type().Implements(textMarshalerType):
+			if err := cborEncode(key, current, depth+1); err != nil {
+				return err
+			}
+		case current.CanInt():
+			cborInteger(key, current.Int())
+		case current.CanUint():
+			cborHeader(key, 0, current.Uint())
+		default:
+			return fmt.Errorf("%w: map key %s", ErrCodecUnsupportedValue, current.Type())
+		}
+
+		entries = append(entries, entry{key: key.Bytes(), value: iterator.Value()})
+	}
+
+	slices.SortFunc(entries, func(a, b entry) int {
+		return bytes.Compare(a.key, b.key)
+	})
+
+	cborHeader(buffer, 5, uint64(len(entries)))
+
+	for _, entry := range entries {
+		buffer.Write(entry.key)
+
+		if err := cborEncode(buffer, entry.value, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cborAssign assigns the given generic item, as decoded by
+// cborDecode, into the given settable value.
+func cborAssign(generic any, value reflect.Value) error {
+	if value.Kind() != reflect.Pointer && value.CanAddr() {
+		pointer := value.Addr()
+
+		if text, ok := generic.(string); ok && pointer.Type().Implements(textUnmarshalerType) {
+			return pointer.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+		}
+
+		if pointer.Type().Implements(jsonUnmarshalerType) {
+			encoded, err := json.Marshal(generic)
+
+			if err != nil {
+				return err
+			}
+
+			return pointer.Interface().(json.Unmarshaler).UnmarshalJSON(encoded)
+		}
+	}
+
+	if generic == nil {
+		value.SetZero()
+
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+
+		return cborAssign(generic, value.Elem())
+	case reflect.Interface:
+		if value.NumMethod() == 0 {
+			value.Set(reflect.ValueOf(generic))
+
+			return nil
+		}
+	case reflect.Bool:
+		if typed, ok := generic.(bool); ok {
+			value.SetBool(typed)
+
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if number, ok := cborInt(generic); ok && !value.OverflowInt(number) {
+			value.SetInt(number)
+
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if number, ok := cborUint(generic); ok && !value.OverflowUint(number) {
+			value.SetUint(number)
+
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if number, ok := cborFloat(generic); ok {
+			value.SetFloat(number)
+
+			return nil
+		}
+	case reflect.String:
+		if typed, ok := generic.(string); ok {
+			value.SetString(typed)
+
+			return nil
+		}
+	case reflect.Slice, reflect.Array:
+		return cborAssignList(generic, value)
+	case reflect.Map:
+		return cborAssignMap(generic, value)
+	case reflect.Struct:
+		items, ok := generic.(map[string]any)
+
+		if !ok {
+			break
+		}
+
+		fields := cborFields(value.Type())
+
+		for key, item := range items {
+			index := slices.IndexFunc(fields, func(field cborField) bool { return field.name == key })
+
+			if index < 0 {
+				index = slices.IndexFunc(fields, func(field cborField) bool { return strings.EqualFold(field.name, key) })
+			}
+
+			if index < 0 {
+				continue
+			}
+
+			field, ok := cborFieldValue(value, fields[index].index, true)
+
+			if !ok {
+				continue
+			}
+
+			if err := cborAssign(item, field); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: cannot decode %T into %s", ErrCodecUnsupportedValue, generic, value.Type())
+}
+
+// cborAssignList assigns the given byte string or
+// array item into the given slice or array value.
+func cborAssignList(generic any, value reflect.Value) error {
+	if data, ok := generic.([]byte); ok && value.Type().Elem().Kind() == reflect.Uint8 {
+		if value.Kind() == reflect.Slice {
+			value.Set(reflect.MakeSlice(value.Type(), len(data), len(data)))
+		} else if len(data) > value.Len() {
+			return fmt.Errorf("%w: cannot decode %d bytes into %s", ErrCodecUnsupportedValue, len(data), value.Type())
+		}
+
+		reflect.Copy(value, reflect.ValueOf(data))
+
+		return nil
+	}
+
+	items, ok := generic.([]any)
+
+	if !ok {
+		return fmt.Errorf("%w: cannot decode %T into %s", ErrCodecUnsupportedValue, generic, value.Type())
+	}
+
+	if value.Kind() == reflect.Slice {
+		value.Set(reflect.MakeSlice(value.Type(), len(items), len(items)))
+	} else if len(items) > value.Len() {
+		return fmt.Errorf("%w: cannot decode %d items into %s", ErrCodecUnsupportedValue, len(items), value.Type())
+	}
+
+	for i, item := range items {
+		if err := cborAssign(item, value.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cborAssignMap assigns the given map item into the given
+// map value, parsing its keys into the map's key type.
+func cborAssignMap(generic any, value reflect.Value) error {
+	items, ok := generic.(map[string]any)
+
+	if !ok {
+		return fmt.Errorf("%w: cannot decode %T into %s", ErrCodecUnsupportedValue, generic, value.Type())
+	}
+
+	if value.IsNil() {
+		value.Set(reflect.MakeMapWithSize(value.Type(), len(items)))
+	}
+
+	for key, item := range items {
+		mapKey := reflect.New(value.Type().Key()).Elem()
+
+		if err := cborAssignKey(key, mapKey); err != nil {
+			return err
+		}
+
+		mapValue := reflect.New(value.Type().Elem()).Elem()
+
+		if err := cborAssign(item, mapValue); err != nil {
+			return err
+		}
+
+		value.SetMapIndex(mapKey, mapValue)
+	}
+
+	return nil
+}
+
+// cborAssignKey assigns the given map key into the given value.
+func cborAssignKey(key string, value reflect.Value) error {
+	if unmarshaler, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(key))
+	}
+
+	switch {
+	case value.Kind() == reflect.String:
+		value.SetString(key)
+
+		return nil
+	case value.CanInt():
+		number, err := strconv.ParseInt(key, 10, 64)
+
+		if err == nil && !value.OverflowInt(number) {
+			value.SetInt(number)
+
+			return nil
+		}
+	case value.CanUint():
+		number, err := strconv.ParseUint(key, 10, 64)
+
+		if err == nil && !value.OverflowUint(number) {
+			value.SetUint(number)
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: cannot decode map key %q into %s", ErrCodecUnsupportedValue, key, value.Type())
+}
+
+// cborInt converts the given generic number into an int64.
+func cborInt(generic any) (int64, bool) {
+	switch typed := generic.(type) {
+	case int64:
+		return typed, true
+	case uint64:
+		return int64(typed), typed <= math.MaxInt64
+	case float64:
+		return int64(typed), typed == math.Trunc(typed) && typed >= math.MinInt64 && typed < math.MaxInt64
+	}
+
+	return 0, false
+}
+
+// cborUint converts the given generic number into an uint64.
+func cborUint(generic any) (uint64, bool) {
+	switch typed := generic.(type) {
+	case int64:
+		return uint64(typed), typed >= 0
+	case uint64:
+		return typed, true
+	case float64:
+		return uint64(typed), typed == math.Trunc(typed) && typed >= 0 && typed < math.MaxUint64
+	}
+
+	return 0, false
+}
+
+// cborFloat converts the given generic number into a float64.
+func cborFloat(generic any) (float64, bool) {
+	switch typed := generic.(type) {
+	case int64:
+		return float64(typed), true
+	case uint64:
+		return float64(typed), true
+	case float64:
+		return typed, true
+	}
+
+	return 0, false
+}
+
+// cborArgument reads the argument of a CBOR item given
+// its additional information. Indefinite lengths return
+// a true second value.
+func cborArgument(reader *bufio.Reader, info byte) (uint64, bool, error) {
+	if info < 24 {
+		return uint64(info), false, nil
+	}
+
+	if info == 31 {
+		return 0, true, nil
+	}
+
+	sizes := map[byte]int{24: 1, 25: 2, 26: 4, 27: 8}
+	size, ok := sizes[info]
+
+	if !ok {
+		return 0, false, fmt.Errorf("%w: invalid additional information %d", ErrCBORMalformed, info)
+	}
+
+	buffer := make([]byte, 8)
+
+	if _, err := io.ReadFull(reader, buffer[8-size:]); err != nil {
+		return 0, false, cborEOF(err)
+	}
+
+	return binary.BigEndian.Uint64(buffer), false, nil
+}
+
+// cborEOF transforms the EOF errors found in the middle
+// of an item into malformed errors.
+func cborEOF(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %w", ErrCBORMalformed, io.ErrUnexpectedEOF)
+	}
+
+	return err
+}
+
+// cborBreak reports whether the next byte is the "break"
+// stop code, consuming it if that's the case.
+func cborBreak(reader *bufio.Reader) (bool, error) {
+	next, err := reader.Peek(1)
+
+	if err != nil {
+		return false, cborEOF(err)
+	}
+
+	if next[0] != 0xff {
+		return false, nil
+	}
+
+	_, _ = reader.ReadByte()
+
+	return true, nil
+}
+
+// cborDecode reads a single CBOR item into its generic value.
+func cborDecode(reader *bufio.Reader, depth int) (any, error) {
+	if depth > cborMaxDepth {
+		return nil, fmt.Errorf("%w: maximum depth exceeded", ErrCBORMalformed)
+	}
+
+	initial, err := reader.ReadByte()
+
+	if err != nil {
+		if depth == 0 {
+			return nil, err
+		}
+
+		return nil, cborEOF(err)
+	}
+
+	major, info := initial>>5, initial&0x1f
+
+	if major == 7 {
+		return cborSimple(reader, info)
+	}
+
+	argument, indefinite, err := cborArgument(reader, info)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case 0:
+		return argument, nil
+	case 1:
+		if argument > math.MaxInt64 {
+			return float64(-1) - float64(argument), nil
+		}
+
+		return -1 - int64(argument), nil
+	case 2, 3:
+		data, err := cborString(reader, major, argument, indefinite, depth)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if major == 2 {
+			return data, nil
+		}
+
+		return string(data), nil
+	case 4:
+		items := make([]any, 0)
+
+		for i := uint64(0); indefinite || i < argument; i++ {
+			if indefinite {
+				if stop, err := cborBreak(reader); stop || err != nil {
+					return items, err
+				}
+			}
+
+			item, err := cborDecode(reader, depth+1)
+
+			if err != nil {
+				return nil, cborEOF(err)
+			}
+
+			items = append(items, item)
+		}
+
+		return items, nil
+	case 5:
+		items := make(map[string]any)
+
+		for i := uint64(0); indefinite || i < argument; i++ {
+			if indefinite {
+				if stop, err := cborBreak(reader); stop || err != nil {
+					return items, err
+				}
+			}
+
+			key, err := cborDecode(reader, depth+1)
+
+			if err != nil {
+				return nil, cborEOF(err)
+			}
+
+			item, err := cborDecode(reader, depth+1)
+
+			if err != nil {
+				return nil, cborEOF(err)
+			}
+
+			if text, ok := key.(string); ok {
+				items[text] = item
+				continue
+			}
+
+			items[fmt.Sprint(key)] = item
+		}
+
+		return items, nil
+	case 6:
+		// Tags are ignored and only
+		// the tagged item is decoded.
+		return cborDecode(reader, depth+1)
+	}
+
+	return nil, fmt.Errorf("%w: invalid major type %d", ErrCBORMalformed, major)
+}
+
+// cborString reads the contents of a byte or text string,
+// including indefinite length strings made of chunks.
+func cborString(reader *bufio.Reader, major byte, length uint64, indefinite bool, depth int) ([]byte, error) {
+	if !indefinite {
+		if length > math.MaxInt32 {
+			return nil, fmt.Errorf("%w: string is too long", ErrCBORMalformed)
+		}
+
+		data := make([]byte, 0, min(length, 4096))
+		buffer := make([]byte, 4096)
+
+		for remaining := length; remaining > 0; {
+			n, err := io.ReadFull(reader, buffer[:min(remaining, uint64(len(buffer)))])
+
+			if err != nil {
+				return nil, cborEOF(err)
+			}
+
+			data = append(data, buffer[:n]...)
+			remaining -= uint64(n)
+		}
+
+		return data, nil
+	}
+
+	data := make([]byte, 0)
+
+	for {
+		if stop, err := cborBreak(reader); stop || err != nil {
+			return data, err
+		}
+
+		chunk, err := cborDecode(reader, depth+1)
+
+		if err != nil {
+			return nil, cborEOF(err)
+		}
+
+		switch typed := chunk.(type) {
+		case []byte:
+			if major != 2 {
+				return nil, fmt.Errorf("%w: invalid string chunk", ErrCBORMalformed)
+			}
+
+			data = append(data, typed...)
+		case string:
+			if major != 3 {
+				return nil, fmt.Errorf("%w: invalid string chunk", ErrCBORMalformed)
+			}
+
+			data = append(data, typed...)
+		default:
+			return nil, fmt.Errorf("%w: invalid string chunk", ErrCBORMalformed)
+		}
+	}
+}
+
+// cborSimple reads a simple value or a floating point number.
+func cborSimple(reader *bufio.Reader, info byte) (any, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		buffer := make([]byte, 2)
+
+		if _, err := io.ReadFull(reader, buffer); err != nil {
+			return nil, cborEOF(err)
+		}
+
+		return cborHalf(binary.BigEndian.Uint16(buffer)), nil
+	case 26:
+		buffer := make([]byte, 4)
+
+		if _, err := io.ReadFull(reader, buffer); err != nil {
+			return nil, cborEOF(err)
+		}
+
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(buffer))), nil
+	case 27:
+		buffer := make([]byte, 8)
+
+		if _, err := io.ReadFull(reader, buffer); err != nil {
+			return nil, cborEOF(err)
+		}
+
+		return math.Float64frombits(binary.BigEndian.Uint64(buffer)), nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported simple value %d", ErrCBORMalformed, info)
+}
+
+// cborHalf converts an IEEE 754 half-precision
+// float into a float64, as shown in RFC 8949.
+func cborHalf(half uint16) float64 {
+	exponent := int(half>>10) & 0x1f
+	mantissa := float64(half & 0x3ff)
+
+	var value float64
+
+	switch exponent {
+	case 0:
+		value = math.Ldexp(mantissa, -24)
+	case 31:
+		if mantissa == 0 {
+			value = math.Inf(1)
+		} else {
+			value = math.NaN()
+		}
+	default:
+		value = math.Ldexp(mantissa+1024, exponent-25)
+	}
+
+	if half&0x8000 != 0 {
+		return -value
+	}
+
+	return value
+}
@@ -0,0 +1,402 @@
+package akumu_test
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/studiolambda/akumu"
+)
+
+type CodecTestPayload struct {
+	Name  string   `json:"name" xml:"name" form:"name"`
+	Age   int      `json:"age" xml:"age" form:"age"`
+	Score float64  `json:"score" xml:"score" form:"score"`
+	Tags  []string `json:"tags" xml:"tags" form:"tags"`
+}
+
+func TestCodecsRoundTrip(t *testing.T) {
+	payload := CodecTestPayload{
+		Name:  "akumu",
+		Age:   -42,
+		Score: 1.5,
+		Tags:  []string{"foo", "bar"},
+	}
+
+	for _, codec := range akumu.Codecs() {
+		t.Run(codec.MediaType(), func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+
+			if err := codec.Encode(buffer, payload); err != nil {
+				t.Fatalf("failed to encode: %v", err)
+			}
+
+			var decoded CodecTestPayload
+
+			if err := codec.Decode(buffer, &decoded); err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+
+			if !reflect.DeepEqual(decoded, payload) {
+				t.Fatalf("expected %+v but got %+v", payload, decoded)
+			}
+		})
+	}
+}
+
+func TestCBORCodecEncode(t *testing.T) {
+	buffer := &bytes.Buffer{}
+
+	if err := (akumu.CBORCodec{}).Encode(buffer, map[string]any{"a": 1, "b": []int{2, 3}}); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+
+	if expected := []byte{0xa2, 0x61, 0x61, 0x01, 0x61, 0x62, 0x82, 0x02, 0x03}; !bytes.Equal(buffer.Bytes(), expected) {
+		t.Fatalf("expected %x but got %x", expected, buffer.Bytes())
+	}
+}
+
+func TestCBORCodecDecodeMalformed(t *testing.T) {
+	var decoded map[string]any
+
+	err := (akumu.CBORCodec{}).Decode(bytes.NewReader([]byte{0xa1, 0x61}), &decoded)
+
+	if !errors.Is(err, akumu.ErrCBORMalformed) {
+		t.Fatalf("expected error %v but got %v", akumu.ErrCBORMalformed, err)
+	}
+}
+
+func TestCBORCodecEncodeBytes(t *testing.T) {
+	buffer := &bytes.Buffer{}
+
+	if err := (akumu.CBORCodec{}).Encode(buffer, []byte{0x01, 0x02}); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+
+	if expected := []byte{0x42, 0x01, 0x02}; !bytes.Equal(buffer.Bytes(), expected) {
+		t.Fatalf("expected %x but got %x", expected, buffer.Bytes())
+	}
+
+	var decoded []byte
+
+	if err := (akumu.CBORCodec{}).Decode(buffer, &decoded); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	if expected := []byte{0x01, 0x02}; !bytes.Equal(decoded, expected) {
+		t.Fatalf("expected %x but got %x", expected, decoded)
+	}
+}
+
+func TestCBORCodecFloats(t *testing.T) {
+	values := []float64{math.NaN(), math.Inf(1), math.Inf(-1), 1.5}
+	buffer := &bytes.Buffer{}
+
+	if err := (akumu.CBORCodec{}).Encode(buffer, values); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+
+	var decoded []float64
+
+	if err := (akumu.CBORCodec{}).Decode(buffer, &decoded); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	if len(decoded) != len(values) {
+		t.Fatalf("expected %d values but got %d", len(values), len(decoded))
+	}
+
+	if !math.IsNaN(decoded[0]) {
+		t.Fatalf("expected NaN but got %v", decoded[0])
+	}
+
+	if !reflect.DeepEqual(decoded[1:], values[1:]) {
+		t.Fatalf("expected %v but got %v", values[1:], decoded[1:])
+	}
+}
+
+func TestCBORCodecStruct(t *testing.T) {
+	type Nested struct {
+		Data []byte            `json:"data"`
+		Sum  [sha1.Size]byte   `json:"sum"`
+		Meta map[int]string    `json:"meta,omitempty"`
+		When time.Time         `json:"when"`
+		Skip string            `json:"-"`
+		Ptr  *CodecTestPayload `json:"ptr"`
+	}
+
+	payload := Nested{
+		Data: []byte("akumu"),
+		Sum:  sha1.Sum([]byte("akumu")),
+		Meta: map[int]string{1: "one", -2: "two"},
+		When: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Skip: "skipped",
+		Ptr:  &CodecTestPayload{Name: "nested", Tags: []string{}},
+	}
+
+	buffer := &bytes.Buffer{}
+
+	if err := (akumu.CBORCodec{}).Encode(buffer, payload); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+
+	var decoded Nested
+
+	if err := (akumu.CBORCodec{}).Decode(buffer, &decoded); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	payload.Skip = ""
+
+	if !reflect.DeepEqual(decoded, payload) {
+		t.Fatalf("expected %+v but got %+v", payload, decoded)
+	}
+}
+
+func TestBuilderEncode(t *testing.T) {
+	handler := func(request *http.Request) error {
+		return akumu.
+			Response(http.StatusOK).
+			Encode("application/x-www-form-urlencoded", CodecTestPayload{Name: "akumu", Age: 10})
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	response := akumu.Record(handler, request)
+
+	if expected := http.StatusOK; response.Code != expected {
+		t.Fatalf("expected status %d but got %d", expected, response.Code)
+	}
+
+	if expected := "application/x-www-form-urlencoded"; response.Header().Get("Content-Type") != expected {
+		t.Fatalf("expected content type %s but got %s", expected, response.Header().Get("Content-Type"))
+	}
+
+	if expected := "age=10&name=akumu&score=0"; response.Body.String() != expected {
+		t.Fatalf("expected body %s but got %s", expected, response.Body.String())
+	}
+}
+
+func TestBuilderEncodeUnknownMediaType(t *testing.T) {
+	handler := func(request *http.Request) error {
+		return akumu.
+			Response(http.StatusOK).
+			Encode("application/unknown", "foo")
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	response := akumu.Record(handler, request)
+
+	if expected := http.StatusInternalServerError; response.Code != expected {
+		t.Fatalf("expected status %d but got %d", expected, response.Code)
+	}
+}
+
+func TestRegisterCodec(t *testing.T) {
+	akumu.RegisterCodec(akumu.JSONCodec{})
+
+	codec, ok := akumu.LookupCodec("application/json")
+
+	if !ok {
+		t.Fatalf("expected codec to be registered")
+	}
+
+	if _, ok := codec.(akumu.JSONCodec); !ok {
+		t.Fatalf("expected codec %T but got %T", akumu.JSONCodec{}, codec)
+	}
+
+	if expected := 4; len(akumu.Codecs()) != expected {
+		t.Fatalf("expected %d codecs but got %d", expected, len(akumu.Codecs()))
+	}
+}
+
+func TestDecode(t *testing.T) {
+	request, err := http.NewRequest(http.MethodPost, "/", strings.NewReader("name=akumu&age=10&tags=foo&tags=bar"))
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	payload, err := akumu.Decode[CodecTestPayload](request)
+
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	expected := CodecTestPayload{Name: "akumu", Age: 10, Tags: []string{"foo", "bar"}}
+
+	if !reflect.DeepEqual(payload, expected) {
+		t.Fatalf("expected %+v but got %+v", expected, payload)
+	}
+}
+
+func TestDecodeUnsupportedMediaType(t *testing.T) {
+	request, err := http.NewRequest(http.MethodPost, "/", strings.NewReader("foo"))
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request.Header.Set("Content-Type", "text/plain")
+
+	_, err = akumu.Decode[CodecTestPayload](request)
+
+	var problem akumu.Problem
+
+	if !errors.As(err, &problem) {
+		t.Fatalf("expected a problem but got %v", err)
+	}
+
+	if expected := http.StatusUnsupportedMediaType; problem.Status != expected {
+		t.Fatalf("expected status %d but got %d", expected, problem.Status)
+	}
+
+	if !errors.Is(err, akumu.ErrCodecNotFound) {
+		t.Fatalf("expected error %v but got %v", akumu.ErrCodecNotFound, err)
+	}
+}
+
+func TestBuilderNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		status int
+		media  string
+	}{
+		{"no accept", "", http.StatusOK, "application/json"},
+		{"exact", "application/xml", http.StatusOK, "application/xml"},
+		{"quality", "application/xml;q=0.5, application/cbor", http.StatusOK, "application/cbor"},
+		{"wildcard", "text/html, */*;q=0.1", http.StatusOK, "application/json"},
+		{"not acceptable", "text/html", http.StatusNotAcceptable, "text/html; charset=utf-8"},
+		{"not acceptable fallback", "image/png", http.StatusNotAcceptable, "text/plain"},
+	}
+
+	handler := func(request *http.Request) error {
+		return akumu.
+			Response(http.StatusOK).
+			Negotiate(CodecTestPayload{Name: "akumu"})
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+			if err != nil {
+				t.Fatalf("failed to create http request: %v", err)
+			}
+
+			if test.accept != "" {
+				request.Header.Set("Accept", test.accept)
+			}
+
+			response := akumu.Record(handler, request)
+
+			if response.Code != test.status {
+				t.Fatalf("expected status %d but got %d", test.status, response.Code)
+			}
+
+			if media := response.Header().Get("Content-Type"); media != test.media {
+				t.Fatalf("expected content type %s but got %s", test.media, media)
+			}
+
+			if expected := "Accept"; response.Header().Get("Vary") != expected {
+				t.Fatalf("expected vary %s but got %s", expected, response.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestBuilderNegotiateChained(t *testing.T) {
+	handler := func(request *http.Request) error {
+		return akumu.
+			Response(http.StatusOK).
+			Negotiate(CodecTestPayload{Name: "akumu"}).
+			Header("Location", "/akumu").
+			Status(http.StatusCreated)
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request.Header.Set("Accept", "application/xml")
+
+	response := akumu.Record(handler, request)
+
+	if expected := http.StatusCreated; response.Code != expected {
+		t.Fatalf("expected status %d but got %d", expected, response.Code)
+	}
+
+	if expected := "/akumu"; response.Header().Get("Location") != expected {
+		t.Fatalf("expected location %s but got %s", expected, response.Header().Get("Location"))
+	}
+
+	if expected := "application/xml"; response.Header().Get("Content-Type") != expected {
+		t.Fatalf("expected content type %s but got %s", expected, response.Header().Get("Content-Type"))
+	}
+
+	if !strings.Contains(response.Body.String(), "<name>akumu</name>") {
+		t.Fatalf("expected body to contain the encoded payload but got %s", response.Body.String())
+	}
+}
+
+func TestBuilderNegotiateUnsupported(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		status int
+		media  string
+	}{
+		{"fallback", "application/xml, application/cbor;q=0.5", http.StatusOK, "application/cbor"},
+		{"wildcard fallback", "application/xml, */*;q=0.1", http.StatusOK, "application/json"},
+		{"xml", "application/xml", http.StatusNotAcceptable, "application/problem+xml"},
+		{"form", "application/x-www-form-urlencoded", http.StatusNotAcceptable, "text/plain"},
+	}
+
+	handler := func(request *http.Request) error {
+		return akumu.
+			Response(http.StatusOK).
+			Negotiate(map[string]any{"a": 1})
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+			if err != nil {
+				t.Fatalf("failed to create http request: %v", err)
+			}
+
+			request.Header.Set("Accept", test.accept)
+
+			response := akumu.Record(handler, request)
+
+			if response.Code != test.status {
+				t.Fatalf("expected status %d but got %d", test.status, response.Code)
+			}
+
+			if media := response.Header().Get("Content-Type"); media != test.media {
+				t.Fatalf("expected content type %s but got %s", test.media, media)
+			}
+		})
+	}
+}
@@ -23,6 +23,53 @@ type ErrServer struct {
 	Request *http.Request
 }
 
+// ErrRedacted is the error that akumu gives to the
+// [OnErrorHook] subscribers whenever a [Problem] is
+// redacted, as determined by [ProblemControls]'s Redact.
+//
+// It keeps the full error chain of the problem, keyed
+// by the opaque occurrence identifier that is sent to
+// the client instead.
+type ErrRedacted struct {
+
+	// Occurrence is the opaque identifier of the
+	// problem occurrence that is sent to the client.
+	Occurrence string
+
+	// Problem is the [Problem] before being redacted,
+	// including the error it comes from, if any.
+	Problem Problem
+
+	// Request stores the actual http request that
+	// failed to execute.
+	Request *http.Request
+}
+
+// ErrPanic is the error that akumu's recover middleware
+// gives to the [OnPanicHook] subscribers and responds with
+// whenever a handler panics.
+//
+// It keeps the stack of the panicking goroutine, which
+// is included in the errors tree of the [Problem] it's
+// responded with.
+type ErrPanic struct {
+
+	// Value stores the value given to panic().
+	Value any
+
+	// Err is the error that the panic
+	// value has been converted to.
+	Err error
+
+	// Stack stores the frames of the panicking
+	// goroutine at the time of the panic.
+	Stack []StackFrame
+
+	// Request stores the actual http request
+	// that panicked.
+	Request *http.Request
+}
+
 var (
 	// ErrServerWriter determines that the
 	// server error comes from executing logic
@@ -67,3 +114,35 @@ func (err ErrServer) Is(target error) bool {
 
 	return ok
 }
+
+// Error implements the error interface
+// for a redacted error.
+func (err ErrRedacted) Error() string {
+	return fmt.Sprintf("redacted problem %s: %s", err.Occurrence, err.Problem)
+}
+
+// Unwrap returns the redacted [Problem], making
+// its error chain reachable using [errors.Is]
+// and [errors.As].
+func (err ErrRedacted) Unwrap() error {
+	return err.Problem
+}
+
+// Error implements the error interface
+// for a panic error.
+func (err ErrPanic) Error() string {
+	return fmt.Sprintf("panic: %s", err.Err)
+}
+
+// Unwrap returns the error that the panic value has
+// been converted to, making it reachable using
+// [errors.Is] and [errors.As].
+func (err ErrPanic) Unwrap() error {
+	return err.Err
+}
+
+// StackFrames implements the [StackTracer] interface,
+// returning the stack of the panicking goroutine.
+func (err ErrPanic) StackFrames() []StackFrame {
+	return err.Stack
+}
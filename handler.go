@@ -1,6 +1,7 @@
 package akumu
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 )
@@ -40,6 +41,11 @@ func handleNoError(writer http.ResponseWriter, request *http.Request, parent *Bu
 }
 
 // handle takes care of responding to a given request.
+//
+// Errors that are not a [Responder] are responded using the
+// [Problem] they are mapped to in the [ProblemRegistry] of the
+// request's [ProblemControls], if any, before falling back to a
+// generic [Problem].
 func handle(writer http.ResponseWriter, request *http.Request, err error, parent *Builder) {
 	if err == nil {
 		handleNoError(writer, request, parent)
@@ -56,6 +62,15 @@ func handle(writer http.ResponseWriter, request *http.Request, err error, parent
 		return
 	}
 
+	registry := Problem{}.
+		controls(request).
+		Registry(Problem{}, request)
+
+	if problem, ok := registry.Lookup(err); ok {
+		handleResponder(writer, request, parent, problem)
+		return
+	}
+
 	if parent != nil {
 		builder := NewProblem(err, parent.status).
 			Respond(request)
@@ -78,12 +93,45 @@ func RawHandler(handler http.Handler) Handler {
 	}
 }
 
+// cleanupsKey is used in the [http.Request]'s context to store
+// the functions that run once the [Handler] has responded.
+type cleanupsKey struct{}
+
 // ServeHTTP implements the [http.Handler] interface to have
 // compatibility with the http package.
+//
+// Functions registered by helpers such as [Upload] run once
+// the handler has responded, in reverse registration order.
 func (handler Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	cleanups := make([]func(), 0)
+	request = request.WithContext(
+		context.WithValue(request.Context(), cleanupsKey{}, &cleanups),
+	)
+
+	defer func() {
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+	}()
+
 	handle(writer, request, handler(request), nil)
 }
 
+// cleanup registers the given function to run once the [Handler]
+// serving the request has responded. It reports false if the
+// request is not being served by a [Handler].
+func cleanup(request *http.Request, function func()) bool {
+	cleanups, ok := request.Context().Value(cleanupsKey{}).(*[]func())
+
+	if !ok {
+		return false
+	}
+
+	*cleanups = append(*cleanups, function)
+
+	return true
+}
+
 // HandlerFunc transforms the [Handler] into an [http.HandlerFunc].
 func (handler Handler) HandlerFunc() http.HandlerFunc {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
@@ -2,24 +2,241 @@ package akumu
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"path"
+	"strings"
 )
 
+var (
+	// ErrJSONEmpty determines that the request
+	// body was empty when JSON was expected.
+	ErrJSONEmpty = errors.New("request body is empty")
+
+	// ErrJSONSyntax determines that the request
+	// body is not well-formed JSON.
+	ErrJSONSyntax = errors.New("request body contains malformed json")
+
+	// ErrJSONType determines that a JSON value of the
+	// request body has a type that cannot be decoded
+	// into the destination value.
+	ErrJSONType = errors.New("request body contains an invalid json type")
+
+	// ErrJSONUnknownField determines that the request
+	// body contains a field that the destination does
+	// not have.
+	ErrJSONUnknownField = errors.New("request body contains an unknown field")
+
+	// ErrJSONTrailingData determines that the request
+	// body contains data after the first JSON value.
+	ErrJSONTrailingData = errors.New("request body contains trailing data")
+
+	// ErrJSONTooLarge determines that the request
+	// body exceeds the maximum allowed size.
+	ErrJSONTooLarge = errors.New("request body is too large")
+
+	// ErrJSONUnsupportedMediaType determines that the
+	// request Content-Type is not one of the allowed.
+	ErrJSONUnsupportedMediaType = errors.New("request body has an unsupported media type")
+)
+
+// JSONOptions are the options that [JSONWith] uses
+// to decode the request body. The zero value is
+// what [JSON] uses.
+type JSONOptions struct {
+
+	// MaxBytes is the maximum size of the request body
+	// in bytes. The body is wrapped using [http.MaxBytesReader]
+	// when it's greater than zero.
+	MaxBytes int64
+
+	// MediaTypes are the media types that the request
+	// Content-Type must match, if any. Wildcards are allowed
+	// using the [path.Match] syntax, for example, "application/*+json".
+	MediaTypes []string
+
+	// AllowTrailingData determines if data after the first
+	// JSON value of the request body is allowed. By default,
+	// it's rejected.
+	AllowTrailingData bool
+}
+
 // JSON decodes the given request payload into `T`
 //
 // This is very usefull for cases where you want
 // to quickly take care of decoding JSON payloads into
 // specific types. It automatically disallows unknown
 // fields and uses [json.Decoder] with the [http.Request.Body].
+// Any data found after the first JSON value is rejected.
+//
+// Decoding errors are returned as a [Problem] with
+// [http.StatusBadRequest] (or [http.StatusRequestEntityTooLarge]
+// if the body exceeds an [http.MaxBytesReader] limit) that
+// describes what went wrong using extension members, so it
+// can directly be returned from a [Handler]. See [JSONProblem]
+// for more information.
+//
+// To limit the body size or the allowed media types, use [JSONWith].
 func JSON[T any](request *http.Request) (T, error) {
+	return JSONWith[T](request, JSONOptions{})
+}
+
+// JSONWith decodes the given request payload into `T`
+// the same way [JSON] does, but using the given [JSONOptions].
+//
+// If the request Content-Type does not match any of the options'
+// media types, a [Problem] with [http.StatusUnsupportedMediaType]
+// is returned. If the body exceeds the options' maximum bytes, a
+// [Problem] with [http.StatusRequestEntityTooLarge] is returned.
+func JSONWith[T any](request *http.Request, options JSONOptions) (T, error) {
 	result := *new(T)
 
-	decoder := json.NewDecoder(request.Body)
+	if len(options.MediaTypes) > 0 {
+		media, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+
+		if !matchesMedia(media, options.MediaTypes) {
+			return result, Problem{
+				Detail: fmt.Sprintf("The request body must be one of: %s.", strings.Join(options.MediaTypes, ", ")),
+				Status: http.StatusUnsupportedMediaType,
+			}.
+				WithError(fmt.Errorf("%w: %q", ErrJSONUnsupportedMediaType, media)).
+				With("supported", options.MediaTypes)
+		}
+	}
+
+	if request.Body == nil {
+		return result, JSONProblem(io.EOF)
+	}
+
+	body := request.Body
+
+	if options.MaxBytes > 0 {
+		body = http.MaxBytesReader(nil, body, options.MaxBytes)
+	}
+
+	decoder := json.NewDecoder(body)
 	decoder.DisallowUnknownFields()
 
 	if err := decoder.Decode(&result); err != nil {
-		return result, err
+		return result, JSONProblem(err)
+	}
+
+	if options.AllowTrailingData {
+		return result, nil
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		var maxBytesErr *http.MaxBytesError
+
+		if errors.As(err, &maxBytesErr) {
+			return result, JSONProblem(err)
+		}
+
+		return result, JSONProblem(fmt.Errorf("%w at byte offset %d", ErrJSONTrailingData, decoder.InputOffset()))
 	}
 
 	return result, nil
 }
+
+// matchesMedia reports whether the given media
+// matches any of the given media patterns.
+func matchesMedia(media string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, media); ok && err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// JSONProblem classifies the given JSON decoding error and
+// returns a [Problem] describing it. The original error is
+// kept in the [Problem] and joined with one of:
+//   - [ErrJSONEmpty]
+//   - [ErrJSONSyntax], with an "offset" extension member.
+//   - [ErrJSONType], with "pointer", "expected" and "actual" extension members.
+//   - [ErrJSONUnknownField], with a "field" extension member.
+//   - [ErrJSONTrailingData]
+//   - [ErrJSONTooLarge], with a "limit" extension member.
+//
+// The status of the [Problem] is [http.StatusBadRequest], except
+// for [ErrJSONTooLarge], which uses [http.StatusRequestEntityTooLarge].
+func JSONProblem(err error) Problem {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.Is(err, ErrJSONTrailingData):
+		return Problem{
+			Detail: "The request body contains data after the first JSON value.",
+			Status: http.StatusBadRequest,
+		}.WithError(err)
+	case errors.As(err, &maxBytesErr):
+		return Problem{
+			Detail: fmt.Sprintf("The request body must not be larger than %d bytes.", maxBytesErr.Limit),
+			Status: http.StatusRequestEntityTooLarge,
+		}.
+			WithError(errors.Join(ErrJSONTooLarge, err)).
+			With("limit", maxBytesErr.Limit)
+	case errors.Is(err, io.EOF):
+		return Problem{
+			Detail: "The request body must not be empty.",
+			Status: http.StatusBadRequest,
+		}.WithError(errors.Join(ErrJSONEmpty, err))
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return Problem{
+			Detail: "The request body contains malformed JSON.",
+			Status: http.StatusBadRequest,
+		}.WithError(errors.Join(ErrJSONSyntax, err))
+	case errors.As(err, &syntaxErr):
+		return Problem{
+			Detail: fmt.Sprintf("The request body contains malformed JSON at byte offset %d.", syntaxErr.Offset),
+			Status: http.StatusBadRequest,
+		}.
+			WithError(errors.Join(ErrJSONSyntax, err)).
+			With("offset", syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		pointer := jsonPointer(typeErr.Field)
+
+		return Problem{
+			Detail: fmt.Sprintf("The request body contains a json %s at %q that cannot be decoded into %s.", typeErr.Value, pointer, typeErr.Type),
+			Status: http.StatusBadRequest,
+		}.
+			WithError(errors.Join(ErrJSONType, err)).
+			With("pointer", pointer).
+			With("expected", typeErr.Type.String()).
+			With("actual", typeErr.Value)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+
+		return Problem{
+			Detail: fmt.Sprintf("The request body contains the unknown field %q.", field),
+			Status: http.StatusBadRequest,
+		}.
+			WithError(errors.Join(ErrJSONUnknownField, err)).
+			With("field", field)
+	}
+
+	return NewProblem(err, http.StatusBadRequest)
+}
+
+// jsonPointer transforms the dotted field path that is found
+// in [json.UnmarshalTypeError] into a JSON pointer (RFC 6901).
+func jsonPointer(field string) string {
+	if field == "" {
+		return ""
+	}
+
+	tokens := strings.Split(field, ".")
+
+	for i, token := range tokens {
+		tokens[i] = escapePointer(token)
+	}
+
+	return "/" + strings.Join(tokens, "/")
+}
@@ -1,6 +1,7 @@
 package akumu_test
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 	"testing"
@@ -34,3 +35,145 @@ func TestJSON(t *testing.T) {
 		t.Fatalf("unexpected bar: %s, expected %s", payload.Bar, expected)
 	}
 }
+
+func TestJSONErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+		key    string
+		value  any
+	}{
+		{"empty", ``, akumu.ErrJSONEmpty, http.StatusBadRequest, "", nil},
+		{"syntax", `{"foo":}`, akumu.ErrJSONSyntax, http.StatusBadRequest, "offset", int64(8)},
+		{"truncated", `{"foo":10`, akumu.ErrJSONSyntax, http.StatusBadRequest, "", nil},
+		{"type", `{"foo":"ten"}`, akumu.ErrJSONType, http.StatusBadRequest, "pointer", "/foo"},
+		{"unknown", `{"baz":true}`, akumu.ErrJSONUnknownField, http.StatusBadRequest, "field", "baz"},
+		{"trailing", `{"foo":10} {}`, akumu.ErrJSONTrailingData, http.StatusBadRequest, "", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+
+			_, err = akumu.JSON[JsonHandlerTestPayload](request)
+
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v but got %v", test.err, err)
+			}
+
+			var problem akumu.Problem
+
+			if !errors.As(err, &problem) {
+				t.Fatalf("expected error to be a problem")
+			}
+
+			if problem.Status != test.status {
+				t.Fatalf("expected status %d but got %d", test.status, problem.Status)
+			}
+
+			if test.key == "" {
+				return
+			}
+
+			if value, _ := problem.Additional(test.key); value != test.value {
+				t.Fatalf("expected %s to be %v but got %v", test.key, test.value, value)
+			}
+		})
+	}
+}
+
+func TestJSONTooLarge(t *testing.T) {
+	request, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"foo":10,"bar":"hello"}`))
+
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+
+	request.Body = http.MaxBytesReader(nil, request.Body, 5)
+
+	_, err = akumu.JSON[JsonHandlerTestPayload](request)
+
+	if !errors.Is(err, akumu.ErrJSONTooLarge) {
+		t.Fatalf("expected error %v but got %v", akumu.ErrJSONTooLarge, err)
+	}
+
+	var problem akumu.Problem
+
+	if errors.As(err, &problem); problem.Status != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status %d but got %d", http.StatusRequestEntityTooLarge, problem.Status)
+	}
+}
+
+func TestJSONWith(t *testing.T) {
+	options := akumu.JSONOptions{
+		MaxBytes:   32,
+		MediaTypes: []string{"application/json", "application/*+json"},
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		media  string
+		err    error
+		status int
+	}{
+		{"json", `{"foo":10}`, "application/json; charset=utf-8", nil, 0},
+		{"suffix", `{"foo":10}`, "application/merge-patch+json", nil, 0},
+		{"media", `{"foo":10}`, "text/plain", akumu.ErrJSONUnsupportedMediaType, http.StatusUnsupportedMediaType},
+		{"missing media", `{"foo":10}`, "", akumu.ErrJSONUnsupportedMediaType, http.StatusUnsupportedMediaType},
+		{"too large", `{"foo":10,"bar":"hello world, hello world"}`, "application/json", akumu.ErrJSONTooLarge, http.StatusRequestEntityTooLarge},
+		{"trailing", `{"foo":10} {}`, "application/json", akumu.ErrJSONTrailingData, http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+
+			request.Header.Set("Content-Type", test.media)
+
+			_, err = akumu.JSONWith[JsonHandlerTestPayload](request, options)
+
+			if test.err == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v but got %v", test.err, err)
+			}
+
+			var problem akumu.Problem
+
+			if errors.As(err, &problem); problem.Status != test.status {
+				t.Fatalf("expected status %d but got %d", test.status, problem.Status)
+			}
+		})
+	}
+}
+
+func TestJSONWithTrailingData(t *testing.T) {
+	request, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"foo":10} {}`))
+
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+
+	options := akumu.JSONOptions{AllowTrailingData: true}
+
+	if _, err := akumu.JSONWith[JsonHandlerTestPayload](request, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
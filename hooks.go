@@ -1,22 +1,153 @@
 package akumu
 
-// OnErrorKey is used in the [http.Request]'s context
-// to store the hook or callback that will run whenever
-// a server error is found.
-type OnErrorKey struct{}
+import (
+	"net/http"
+	"slices"
+	"time"
+)
+
+// HooksKey is used in the [http.Request]'s context
+// to store the [Hooks] that will run during the
+// lifecycle of the request.
+type HooksKey struct{}
+
+// Hooks stores the subscribers of each of the
+// lifecycle events of a request.
+//
+// Hooks are installed in the request's context by the
+// hooks middleware, which chains them after the ones that
+// are already installed, so that multiple subscribers of
+// the same event run in the order they were installed.
+type Hooks struct {
+
+	// OnRequest stores the subscribers that run
+	// whenever a request is received.
+	OnRequest []OnRequestHook
+
+	// OnResponse stores the subscribers that run
+	// whenever a response has been written.
+	OnResponse []OnResponseHook
+
+	// OnError stores the subscribers that run
+	// whenever a server error is found.
+	OnError []OnErrorHook
 
-// OnErrorHook stores the current handler that will take
-// care of handling a server error in case it's found.
+	// OnPanic stores the subscribers that run whenever
+	// a handler panics and it's recovered.
+	OnPanic []OnPanicHook
+
+	// OnStreamClosed stores the subscribers that run
+	// whenever a [Builder.Stream] response is closed.
+	OnStreamClosed []OnStreamClosedHook
+}
+
+// OnRequestHook is a subscriber that runs
+// whenever a request is received.
+type OnRequestHook func(request *http.Request)
+
+// OnResponseHook is a subscriber that runs whenever a
+// response has been written, with its [HookResponse].
+type OnResponseHook func(request *http.Request, response HookResponse)
+
+// OnErrorHook is a subscriber that runs whenever
+// a server error is found.
 //
-// The `error` is always a [ErrServer] and it's joined with
-// either of those:
+// The `error` is either an [ErrRedacted], when a [Problem] is
+// redacted, or a [ErrServer] joined with either of those:
 //   - [ErrServerWriter]
 //   - [ErrServerBody]
 //   - [ErrServerStream]
 //   - [ErrServerDefault]
-//
-// To "extend" the hook, you can make use of composition
-// to also call the previous function in the new one, creating
-// a chain of handlers. Keep in mind you will need to check
-// for `nil` if that was the case.
-type OnErrorHook func(error)
+type OnErrorHook func(err error)
+
+// OnPanicHook is a subscriber that runs whenever a handler
+// panics and it's recovered, with the [ErrPanic] that
+// keeps the stack of the panicking goroutine.
+type OnPanicHook func(err ErrPanic)
+
+// OnStreamClosedHook is a subscriber that runs whenever a
+// [Builder.Stream] response is closed. The `err` is the
+// request's context error when the client went away
+// or nil when the stream channel was closed.
+type OnStreamClosedHook func(request *http.Request, err error)
+
+// HookResponse stores the information of a written
+// response, as given to the [OnResponseHook].
+type HookResponse struct {
+
+	// Status is the http status code of the response.
+	Status int
+
+	// Bytes is the number of bytes
+	// written in the response body.
+	Bytes int64
+
+	// Duration is the time it took to
+	// handle the request and respond.
+	Duration time.Duration
+}
+
+// RequestHooks returns the [Hooks] installed in the
+// given request's context, if any.
+func RequestHooks(request *http.Request) Hooks {
+	hooks, _ := request.Context().Value(HooksKey{}).(Hooks)
+
+	return hooks
+}
+
+// Merge returns new [Hooks] with the subscribers of the
+// given hooks chained after the subscribers of these ones.
+func (hooks Hooks) Merge(other Hooks) Hooks {
+	return Hooks{
+		OnRequest:      slices.Concat(hooks.OnRequest, other.OnRequest),
+		OnResponse:     slices.Concat(hooks.OnResponse, other.OnResponse),
+		OnError:        slices.Concat(hooks.OnError, other.OnError),
+		OnPanic:        slices.Concat(hooks.OnPanic, other.OnPanic),
+		OnStreamClosed: slices.Concat(hooks.OnStreamClosed, other.OnStreamClosed),
+	}
+}
+
+// RunRequest runs the [OnRequestHook] subscribers.
+func (hooks Hooks) RunRequest(request *http.Request) {
+	for _, hook := range hooks.OnRequest {
+		if hook != nil {
+			hook(request)
+		}
+	}
+}
+
+// RunResponse runs the [OnResponseHook] subscribers.
+func (hooks Hooks) RunResponse(request *http.Request, response HookResponse) {
+	for _, hook := range hooks.OnResponse {
+		if hook != nil {
+			hook(request, response)
+		}
+	}
+}
+
+// RunError runs the [OnErrorHook] subscribers.
+func (hooks Hooks) RunError(err error) {
+	for _, hook := range hooks.OnError {
+		if hook != nil {
+			hook(err)
+		}
+	}
+}
+
+// RunPanic runs the [OnPanicHook] subscribers.
+func (hooks Hooks) RunPanic(err ErrPanic) {
+	for _, hook := range hooks.OnPanic {
+		if hook != nil {
+			hook(err)
+		}
+	}
+}
+
+// RunStreamClosed runs the [OnStreamClosedHook] subscribers.
+func (hooks Hooks) RunStreamClosed(request *http.Request, err error) {
+	for _, hook := range hooks.OnStreamClosed {
+		if hook != nil {
+			hook(request, err)
+		}
+	}
+}
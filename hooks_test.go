@@ -0,0 +1,90 @@
+package akumu_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+)
+
+func TestHooksMerge(t *testing.T) {
+	calls := make([]string, 0)
+
+	first := akumu.Hooks{
+		OnRequest: []akumu.OnRequestHook{func(*http.Request) { calls = append(calls, "first") }},
+	}
+
+	second := akumu.Hooks{
+		OnRequest: []akumu.OnRequestHook{nil, func(*http.Request) { calls = append(calls, "second") }},
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	first.Merge(second).RunRequest(request)
+
+	if len(calls) != 2 || calls[0] != "first" || calls[1] != "second" {
+		t.Fatalf("expected hooks to run in order but got %v", calls)
+	}
+
+	if expected := 1; len(first.OnRequest) != expected {
+		t.Fatalf("expected %d hooks but got %d", expected, len(first.OnRequest))
+	}
+}
+
+func TestHooksOnError(t *testing.T) {
+	reported := make([]error, 0)
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request = request.WithContext(context.WithValue(request.Context(), akumu.HooksKey{}, akumu.Hooks{
+		OnError: []akumu.OnErrorHook{func(err error) { reported = append(reported, err) }},
+	}))
+
+	akumu.Record(func(*http.Request) error {
+		return akumu.Response(http.StatusBadGateway)
+	}, request)
+
+	if expected := 1; len(reported) != expected {
+		t.Fatalf("expected %d errors but got %d", expected, len(reported))
+	}
+
+	if !errors.Is(reported[0], akumu.ErrServerDefault) {
+		t.Fatalf("expected error to be %v but got %v", akumu.ErrServerDefault, reported[0])
+	}
+}
+
+func TestHooksOnStreamClosed(t *testing.T) {
+	closed := make([]error, 0)
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request = request.WithContext(context.WithValue(request.Context(), akumu.HooksKey{}, akumu.Hooks{
+		OnStreamClosed: []akumu.OnStreamClosedHook{func(_ *http.Request, err error) { closed = append(closed, err) }},
+	}))
+
+	akumu.Record(func(*http.Request) error {
+		stream := make(chan []byte, 1)
+		stream <- []byte("message")
+		close(stream)
+
+		return akumu.Response(http.StatusOK).Stream(stream)
+	}, request)
+
+	if len(closed) != 1 || closed[0] != nil {
+		t.Fatalf("expected the stream to close without error but got %v", closed)
+	}
+}
@@ -0,0 +1,230 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/studiolambda/akumu"
+)
+
+// AccessLogFormat determines the format of
+// the messages logged by the [AccessLogger].
+type AccessLogFormat int
+
+const (
+	// AccessLogStructured logs a fixed message, relying
+	// only on the structured attributes of the record.
+	AccessLogStructured AccessLogFormat = iota
+
+	// AccessLogCommon logs the request using the
+	// Common Log Format as the message.
+	AccessLogCommon
+
+	// AccessLogCombined logs the request using the
+	// Combined Log Format as the message.
+	AccessLogCombined
+)
+
+// AccessLog stores the options of the [AccessLogger].
+type AccessLog struct {
+
+	// Logger is the logger used to log the
+	// requests. Defaults to [slog.Default].
+	Logger *slog.Logger
+
+	// Format is the format of the logged messages. The structured
+	// attributes are logged regardless of the format.
+	Format AccessLogFormat
+
+	// Level determines the level of the logged request given the
+	// response status code. Defaults to [slog.LevelError] for server
+	// errors, [slog.LevelWarn] for client errors and [slog.LevelInfo]
+	// for the rest. See [AccessLogLevels] to build it from rules.
+	Level func(status int) slog.Level
+
+	// RequestIDHeader is the request header that stores the
+	// request ID. Defaults to "X-Request-Id".
+	RequestIDHeader string
+}
+
+// AccessLogLevels is a helper that generates the level function of an
+// [AccessLog] from the given rules, keyed by the minimum status code
+// they apply to. The rule with the highest status code that's lower or
+// equal to the response status code is used, defaulting to [slog.LevelInfo].
+//
+// For example, {400: slog.LevelWarn, 500: slog.LevelError} logs
+// client errors as warnings and server errors as errors.
+func AccessLogLevels(rules map[int]slog.Level) func(status int) slog.Level {
+	statuses := make([]int, 0, len(rules))
+
+	for status := range rules {
+		statuses = append(statuses, status)
+	}
+
+	slices.Sort(statuses)
+
+	return func(status int) slog.Level {
+		level := slog.LevelInfo
+
+		for _, minimum := range statuses {
+			if minimum > status {
+				break
+			}
+
+			level = rules[minimum]
+		}
+
+		return level
+	}
+}
+
+// defaultAccessLogLevel is the default level function of an [AccessLog].
+var defaultAccessLogLevel = AccessLogLevels(map[int]slog.Level{
+	http.StatusBadRequest:          slog.LevelWarn,
+	http.StatusInternalServerError: slog.LevelError,
+})
+
+// AccessLogger middleware logs every http request once it has
+// been responded, using the given [AccessLog] options.
+//
+// Each record includes the method, url, route pattern, status code,
+// bytes written, duration, remote address and request ID, if any.
+//
+// The route pattern is known when the middleware is mounted through
+// [akumu.Router.Use], as described in [HooksWith]. Outside of an
+// [akumu.Router], it requires Go 1.23 or later.
+func AccessLogger(options AccessLog) akumu.Middleware {
+	return func(handler http.Handler) http.Handler {
+		return AccessLoggerWith(handler, options)
+	}
+}
+
+// AccessLoggerDefault middleware logs every http request once
+// it has been responded, using the default [AccessLog] options.
+func AccessLoggerDefault() akumu.Middleware {
+	return AccessLogger(AccessLog{})
+}
+
+// AccessLoggerWith middleware logs every http request once it has
+// been responded but this time accepting the handler as a parameter.
+//
+// The response is recorded using an [akumu.OnResponseHook], whose
+// [http.ResponseWriter] can be unwrapped by [http.ResponseController]
+// to keep flushing and hijacking working.
+func AccessLoggerWith(handler http.Handler, options AccessLog) http.Handler {
+	if options.Logger == nil {
+		options.Logger = slog.Default()
+	}
+
+	if options.Level == nil {
+		options.Level = defaultAccessLogLevel
+	}
+
+	if options.RequestIDHeader == "" {
+		options.RequestIDHeader = "X-Request-Id"
+	}
+
+	return HooksWith(handler, akumu.Hooks{
+		OnResponse: []akumu.OnResponseHook{func(request *http.Request, response akumu.HookResponse) {
+			attributes := []any{
+				"method", request.Method,
+				"url", request.URL.String(),
+				"pattern", requestPattern(request),
+				"status", response.Status,
+				"bytes", response.Bytes,
+				"duration", response.Duration,
+				"remote", request.RemoteAddr,
+			}
+
+			if id := request.Header.Get(options.RequestIDHeader); id != "" {
+				attributes = append(attributes, "request_id", id)
+			}
+
+			message := "http request"
+
+			switch options.Format {
+			case AccessLogCommon:
+				message = commonLogFormat(request, response)
+			case AccessLogCombined:
+				message = combinedLogFormat(request, response)
+			}
+
+			options.Logger.Log(
+				request.Context(),
+				options.Level(response.Status),
+				message,
+				attributes...,
+			)
+		}},
+	})
+}
+
+// commonLogFormat returns the Common Log Format line
+// of the given request and its response.
+func commonLogFormat(request *http.Request, response akumu.HookResponse) string {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+
+	if err != nil || host == "" {
+		host = request.RemoteAddr
+	}
+
+	user := "-"
+
+	if username, _, ok := request.BasicAuth(); ok && username != "" {
+		user = logEscape(username)
+	} else if request.URL.User != nil && request.URL.User.Username() != "" {
+		user = logEscape(request.URL.User.Username())
+	}
+
+	bytes := "-"
+
+	if response.Bytes > 0 {
+		bytes = strconv.FormatInt(response.Bytes, 10)
+	}
+
+	return fmt.Sprintf(
+		"%s - %s [%s] %q %d %s",
+		logValue(host),
+		user,
+		time.Now().Add(-response.Duration).Format("02/Jan/2006:15:04:05 -0700"),
+		request.Method+" "+request.URL.RequestURI()+" "+request.Proto,
+		response.Status,
+		bytes,
+	)
+}
+
+// combinedLogFormat returns the Combined Log Format
+// line of the given request and its response.
+func combinedLogFormat(request *http.Request, response akumu.HookResponse) string {
+	return fmt.Sprintf(
+		"%s %q %q",
+		commonLogFormat(request, response),
+		logValue(request.Referer()),
+		logValue(request.UserAgent()),
+	)
+}
+
+// logEscape escapes the given unquoted value of a log format,
+// using Go escape sequences for quotes, backslashes, spaces and
+// non-printable characters, so that it can't break its fields.
+func logEscape(value string) string {
+	quoted := strconv.Quote(value)
+
+	return strings.ReplaceAll(quoted[1:len(quoted)-1], " ", `\x20`)
+}
+
+// logValue returns the given value or "-"
+// if it's empty, as used in log formats.
+func logValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
@@ -0,0 +1,222 @@
+package middleware_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+	"github.com/studiolambda/akumu/middleware"
+)
+
+type AccessTestWriter struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (writer *AccessTestWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	writer.hijacked = true
+
+	return nil, nil, nil
+}
+
+func accessRecords(t *testing.T, buffer *bytes.Buffer) []map[string]any {
+	records := make([]map[string]any, 0)
+	scanner := bufio.NewScanner(buffer)
+
+	for scanner.Scan() {
+		record := make(map[string]any)
+
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("failed to decode log record: %v", err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func accessLog(t *testing.T, options middleware.AccessLog, handler http.Handler, request *http.Request) map[string]any {
+	buffer := &bytes.Buffer{}
+	options.Logger = slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	akumu.RecordHandler(middleware.AccessLoggerWith(handler, options), request)
+
+	records := accessRecords(t, buffer)
+
+	if len(records) != 1 {
+		t.Fatalf("expected 1 log record but got %d", len(records))
+	}
+
+	return records[0]
+}
+
+func TestAccessLoggerFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  middleware.AccessLogFormat
+		user    string
+		status  int
+		body    string
+		message string
+	}{
+		{"structured", middleware.AccessLogStructured, "frank", http.StatusOK, "hello", `^http request$`},
+		{"common", middleware.AccessLogCommon, "frank", http.StatusOK, "hello", `^192\.0\.2\.1 - frank \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /users\?page=1 HTTP/1\.1" 200 5$`},
+		{"common no body", middleware.AccessLogCommon, "", http.StatusNoContent, "", `^192\.0\.2\.1 - - \[[^\]]+\] "GET /users\?page=1 HTTP/1\.1" 204 -$`},
+		{"common escaped user", middleware.AccessLogCommon, "fr ank\"", http.StatusOK, "hello", `^192\.0\.2\.1 - fr\\x20ank\\" \[[^\]]+\] "GET /users\?page=1 HTTP/1\.1" 200 5$`},
+		{"combined", middleware.AccessLogCombined, "frank", http.StatusOK, "hello", `^192\.0\.2\.1 - frank \[[^\]]+\] "GET /users\?page=1 HTTP/1\.1" 200 5 "http://example\.com/" "akumu \\"test\\""$`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/users?page=1", nil)
+			request.RemoteAddr = "192.0.2.1:1234"
+			request.Header.Set("Referer", "http://example.com/")
+			request.Header.Set("User-Agent", `akumu "test"`)
+
+			if test.user != "" {
+				request.SetBasicAuth(test.user, "secret")
+			}
+
+			handler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				writer.WriteHeader(test.status)
+				_, _ = writer.Write([]byte(test.body))
+			})
+
+			record := accessLog(t, middleware.AccessLog{Format: test.format}, handler, request)
+			message, _ := record["msg"].(string)
+
+			if !regexp.MustCompile(test.message).MatchString(message) {
+				t.Fatalf("expected message to match %s but got %s", test.message, message)
+			}
+		})
+	}
+}
+
+func TestAccessLoggerLevels(t *testing.T) {
+	tests := []struct {
+		name   string
+		level  func(status int) slog.Level
+		status int
+		result string
+	}{
+		{"default success", nil, http.StatusOK, "INFO"},
+		{"default client error", nil, http.StatusNotFound, "WARN"},
+		{"default server error", nil, http.StatusServiceUnavailable, "ERROR"},
+		{"rules below", middleware.AccessLogLevels(map[int]slog.Level{300: slog.LevelWarn}), http.StatusOK, "INFO"},
+		{"rules exact", middleware.AccessLogLevels(map[int]slog.Level{300: slog.LevelWarn}), http.StatusMultipleChoices, "WARN"},
+		{"rules highest", middleware.AccessLogLevels(map[int]slog.Level{0: slog.LevelDebug, 400: slog.LevelError}), http.StatusTeapot, "ERROR"},
+		{"rules lowest", middleware.AccessLogLevels(map[int]slog.Level{0: slog.LevelDebug, 400: slog.LevelError}), http.StatusOK, "DEBUG"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			handler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				writer.WriteHeader(test.status)
+			})
+
+			record := accessLog(t, middleware.AccessLog{Level: test.level}, handler, request)
+
+			if record["level"] != test.result {
+				t.Fatalf("expected level %s but got %v", test.result, record["level"])
+			}
+		})
+	}
+}
+
+func TestAccessLoggerAttributes(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+
+	router := akumu.NewRouter()
+	router.Use(middleware.AccessLogger(middleware.AccessLog{Logger: logger}))
+	router.Get("/users/{id}", func(request *http.Request) error {
+		return akumu.Response(http.StatusCreated).Text("created")
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/users/10", nil)
+	request.Header.Set("X-Request-Id", "abc123")
+
+	akumu.RecordHandler(router, request)
+
+	records := accessRecords(t, buffer)
+
+	if len(records) != 1 {
+		t.Fatalf("expected 1 log record but got %d", len(records))
+	}
+
+	expected := map[string]any{
+		"method":     http.MethodGet,
+		"url":        "/users/10",
+		"pattern":    "GET /users/{id}",
+		"status":     float64(http.StatusCreated),
+		"bytes":      float64(len("created")),
+		"request_id": "abc123",
+	}
+
+	for key, value := range expected {
+		if records[0][key] != value {
+			t.Fatalf("expected %s %v but got %v", key, value, records[0][key])
+		}
+	}
+
+	if _, ok := records[0]["duration"]; !ok {
+		t.Fatalf("expected duration attribute but got none")
+	}
+}
+
+func TestAccessLoggerRequestIDHeader(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("X-Request-Id", "ignored")
+	request.Header.Set("X-Correlation-Id", "abc123")
+
+	handler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	record := accessLog(t, middleware.AccessLog{RequestIDHeader: "X-Correlation-Id"}, handler, request)
+
+	if record["request_id"] != "abc123" {
+		t.Fatalf("expected request_id abc123 but got %v", record["request_id"])
+	}
+}
+
+func TestAccessLoggerResponseController(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	writer := &AccessTestWriter{ResponseRecorder: httptest.NewRecorder()}
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	handler := middleware.AccessLoggerWith(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		controller := http.NewResponseController(writer)
+
+		_, _ = writer.Write([]byte("hello"))
+
+		if err := controller.Flush(); err != nil {
+			t.Fatalf("expected the writer to flush but got %v", err)
+		}
+
+		if _, _, err := controller.Hijack(); err != nil {
+			t.Fatalf("expected the writer to hijack but got %v", err)
+		}
+	}), middleware.AccessLog{Logger: logger})
+
+	handler.ServeHTTP(writer, request)
+
+	if !writer.Flushed {
+		t.Fatalf("expected the original writer to be flushed")
+	}
+
+	if !writer.hijacked {
+		t.Fatalf("expected the original writer to be hijacked")
+	}
+
+	if records := accessRecords(t, buffer); len(records) != 1 || records[0]["bytes"] != float64(5) {
+		t.Fatalf("expected 1 log record with 5 bytes but got %v", records)
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/studiolambda/akumu"
+)
+
+// Hooks installs the given [akumu.Hooks] in the [http.Request],
+// chained after the ones that are already installed, if any.
+//
+// The [akumu.OnRequestHook] and [akumu.OnResponseHook] subscribers
+// of the given hooks run before and after handling the request,
+// while the rest run whenever their event happens.
+func Hooks(hooks akumu.Hooks) akumu.Middleware {
+	return func(handler http.Handler) http.Handler {
+		return HooksWith(handler, hooks)
+	}
+}
+
+// HooksWith installs the given [akumu.Hooks] in the [http.Request]
+// but this time accepting the handler as a parameter.
+//
+// The [http.ResponseWriter] is only wrapped to record the response
+// when there are [akumu.OnResponseHook] subscribers, so the handler
+// gets the original one otherwise.
+//
+// The [akumu.OnResponseHook] subscribers are given the request that's
+// passed to the handler. Mount it through [akumu.Router.Use], whose
+// middlewares run once the route is matched, for [akumu.RequestPattern]
+// to return the route pattern, as middlewares mounted around the router
+// see the request before it's routed.
+func HooksWith(handler http.Handler, hooks akumu.Hooks) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		start := time.Now()
+		chained := akumu.RequestHooks(request).Merge(hooks)
+		request = request.WithContext(
+			context.WithValue(request.Context(), akumu.HooksKey{}, chained),
+		)
+
+		hooks.RunRequest(request)
+
+		if len(hooks.OnResponse) == 0 {
+			handler.ServeHTTP(writer, request)
+
+			return
+		}
+
+		recorder := &responseWriter{ResponseWriter: writer}
+
+		handler.ServeHTTP(recorder, request)
+
+		hooks.RunResponse(request, akumu.HookResponse{
+			Status:   recorder.Status(),
+			Bytes:    recorder.bytes,
+			Duration: time.Since(start),
+		})
+	})
+}
@@ -0,0 +1,201 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/studiolambda/akumu"
+	"github.com/studiolambda/akumu/middleware"
+)
+
+func TestHooksInstall(t *testing.T) {
+	requests := 0
+	installed := akumu.Hooks{}
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	handler := middleware.Hooks(akumu.Hooks{
+		OnRequest: []akumu.OnRequestHook{func(*http.Request) {
+			requests++
+		}},
+		OnError: []akumu.OnErrorHook{func(error) {}},
+	})(akumu.Handler(func(request *http.Request) error {
+		installed = akumu.RequestHooks(request)
+
+		return nil
+	}))
+
+	akumu.RecordHandler(handler, request)
+
+	if requests != 1 {
+		t.Fatalf("expected 1 request hook run but got %d", requests)
+	}
+
+	if len(installed.OnRequest) != 1 || len(installed.OnError) != 1 {
+		t.Fatalf("expected the hooks to be installed in the request but got %+v", installed)
+	}
+}
+
+func TestHooksResponse(t *testing.T) {
+	responses := make([]akumu.HookResponse, 0)
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	handler := middleware.HooksWith(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+
+		if err := http.NewResponseController(writer).Flush(); err != nil {
+			t.Fatalf("expected the writer to flush but got %v", err)
+		}
+
+		writer.WriteHeader(http.StatusCreated)
+		_, _ = writer.Write([]byte("hello"))
+		_, _ = writer.Write([]byte(" world"))
+	}), akumu.Hooks{
+		OnResponse: []akumu.OnResponseHook{func(request *http.Request, response akumu.HookResponse) {
+			responses = append(responses, response)
+		}},
+	})
+
+	akumu.RecordHandler(handler, request)
+
+	if len(responses) != 1 {
+		t.Fatalf("expected 1 response hook run but got %d", len(responses))
+	}
+
+	if responses[0].Status != http.StatusCreated {
+		t.Fatalf("expected status %d but got %d", http.StatusCreated, responses[0].Status)
+	}
+
+	if expected := int64(11); responses[0].Bytes != expected {
+		t.Fatalf("expected %d bytes but got %d", expected, responses[0].Bytes)
+	}
+
+	if responses[0].Duration < 5*time.Millisecond {
+		t.Fatalf("expected duration of at least 5ms but got %s", responses[0].Duration)
+	}
+}
+
+func TestHooksResponseDefaultStatus(t *testing.T) {
+	status := 0
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	handler := middleware.HooksWith(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), akumu.Hooks{
+		OnResponse: []akumu.OnResponseHook{func(request *http.Request, response akumu.HookResponse) {
+			status = response.Status
+		}},
+	})
+
+	akumu.RecordHandler(handler, request)
+
+	if status != http.StatusOK {
+		t.Fatalf("expected status %d but got %d", http.StatusOK, status)
+	}
+}
+
+func TestHooksChain(t *testing.T) {
+	events := make([]string, 0)
+
+	subscriber := func(name string) akumu.Hooks {
+		return akumu.Hooks{
+			OnError: []akumu.OnErrorHook{func(error) {
+				events = append(events, name+" error")
+			}},
+			OnResponse: []akumu.OnResponseHook{func(*http.Request, akumu.HookResponse) {
+				events = append(events, name+" response")
+			}},
+		}
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	handler := middleware.Hooks(subscriber("outer"))(
+		middleware.Hooks(subscriber("inner"))(
+			akumu.Handler(func(*http.Request) error {
+				return errors.New("failed")
+			}),
+		),
+	)
+
+	akumu.RecordHandler(handler, request)
+
+	expected := []string{"outer error", "inner error", "inner response", "outer response"}
+
+	if !slices.Equal(events, expected) {
+		t.Fatalf("expected events %v but got %v", expected, events)
+	}
+}
+
+func TestHooksPanic(t *testing.T) {
+	panics := 0
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	handler := middleware.Hooks(akumu.Hooks{
+		OnPanic: []akumu.OnPanicHook{func(akumu.ErrPanic) {
+			panics++
+		}},
+	})(middleware.Recover()(panicking("boom")))
+
+	akumu.RecordHandler(handler, request)
+
+	if panics != 1 {
+		t.Fatalf("expected 1 panic hook run but got %d", panics)
+	}
+}
+
+func TestHooksStreamClosed(t *testing.T) {
+	closed := make([]error, 0)
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	handler := middleware.Hooks(akumu.Hooks{
+		OnStreamClosed: []akumu.OnStreamClosedHook{func(request *http.Request, err error) {
+			closed = append(closed, err)
+		}},
+	})(akumu.Handler(func(*http.Request) error {
+		stream := make(chan []byte, 1)
+		stream <- []byte("hello")
+		close(stream)
+
+		return akumu.Response(http.StatusOK).Stream(stream)
+	}))
+
+	response := akumu.RecordHandler(handler, request)
+
+	if expected := "hello"; response.Body.String() != expected {
+		t.Fatalf("expected body %s but got %s", expected, response.Body.String())
+	}
+
+	if len(closed) != 1 || closed[0] != nil {
+		t.Fatalf("expected 1 stream closed hook run without error but got %v", closed)
+	}
+}
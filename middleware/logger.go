@@ -1,7 +1,7 @@
 package middleware
 
 import (
-	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 
@@ -27,24 +27,54 @@ func LoggerDefault() akumu.Middleware {
 // LoggerWith middleware sets a [slog.Logger] instance
 // as the logger for any http requests but this time accepting
 // the handler as a parameter.
+//
+// Server errors are logged using an [akumu.OnErrorHook], including
+// the full error chain of [akumu.ErrRedacted] keyed by its occurrence,
+// and panics using an [akumu.OnPanicHook], including their stack.
+// The [http.ResponseWriter] is given to the handler as is.
 func LoggerWith(handler http.Handler, logger *slog.Logger) http.Handler {
-	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
-		parent, hasParent := request.Context().Value(akumu.OnErrorKey{}).(akumu.OnErrorHook)
-
-		handler.ServeHTTP(writer, request.WithContext(
-			context.WithValue(request.Context(), akumu.OnErrorKey{}, func(err akumu.ErrServer) {
-				if hasParent && parent != nil {
-					parent(err)
-				}
+	return HooksWith(handler, akumu.Hooks{
+		OnError: []akumu.OnErrorHook{func(err error) {
+			redacted := akumu.ErrRedacted{}
 
+			if errors.As(err, &redacted) {
 				logger.ErrorContext(
-					request.Context(),
-					"server error",
-					"code", err.Code,
-					"text", http.StatusText(err.Code),
-					"url", err.Request.URL,
+					redacted.Request.Context(),
+					"redacted problem",
+					"occurrence", redacted.Occurrence,
+					"code", redacted.Problem.Status,
+					"text", http.StatusText(redacted.Problem.Status),
+					"url", redacted.Request.URL,
+					"error", redacted.Problem.Unwrap(),
 				)
-			}),
-		))
+
+				return
+			}
+
+			serverErr := akumu.ErrServer{}
+
+			if !errors.As(err, &serverErr) {
+				logger.Error("server error", "error", err)
+
+				return
+			}
+
+			logger.ErrorContext(
+				serverErr.Request.Context(),
+				"server error",
+				"code", serverErr.Code,
+				"text", http.StatusText(serverErr.Code),
+				"url", serverErr.Request.URL,
+			)
+		}},
+		OnPanic: []akumu.OnPanicHook{func(err akumu.ErrPanic) {
+			logger.ErrorContext(
+				err.Request.Context(),
+				"panic",
+				"url", err.Request.URL,
+				"error", err.Err,
+				"stack", err.Stack,
+			)
+		}},
 	})
 }
@@ -0,0 +1,43 @@
+package middleware_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+	"github.com/studiolambda/akumu/middleware"
+)
+
+func TestLoggerWriter(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buffer, nil))
+	original := &AccessTestWriter{ResponseRecorder: httptest.NewRecorder()}
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	handler := middleware.Logger(logger)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if writer != http.ResponseWriter(original) {
+			t.Fatalf("expected the original writer but got %T", writer)
+		}
+
+		if _, ok := writer.(http.Hijacker); !ok {
+			t.Fatalf("expected the writer to be a hijacker")
+		}
+
+		akumu.RequestHooks(request).RunError(akumu.ErrServer{Code: http.StatusBadGateway, Request: request})
+	}))
+
+	handler.ServeHTTP(original, request)
+
+	if !strings.Contains(buffer.String(), "server error") || !strings.Contains(buffer.String(), "code=502") {
+		t.Fatalf("expected the server error to be logged but got %s", buffer.String())
+	}
+}
@@ -0,0 +1,20 @@
+//go:build go1.23
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/studiolambda/akumu"
+)
+
+// requestPattern returns the pattern of the route that matched
+// the given request, if any, either from an [akumu.Router] or
+// from the [http.ServeMux] that routed it.
+func requestPattern(request *http.Request) string {
+	if pattern := akumu.RequestPattern(request); pattern != "" {
+		return pattern
+	}
+
+	return request.Pattern
+}
@@ -0,0 +1,17 @@
+//go:build !go1.23
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/studiolambda/akumu"
+)
+
+// requestPattern returns the pattern of the route that matched
+// the given request, if any. Before Go 1.23, [http.Request] does
+// not expose the pattern of the [http.ServeMux], so only the ones
+// of an [akumu.Router] are known.
+func requestPattern(request *http.Request) string {
+	return akumu.RequestPattern(request)
+}
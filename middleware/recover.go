@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"runtime/debug"
 
 	"github.com/studiolambda/akumu"
 )
@@ -15,6 +16,23 @@ var (
 	ErrRecoverUnexpectedError = errors.New("an unexpected error occurred")
 )
 
+// Recovery stores the options of the [Recoverer].
+type Recovery struct {
+
+	// Handle converts the recover() value into the error that
+	// [akumu.Failed] receives. Defaults to the conversion
+	// described in [Recover].
+	Handle func(value any) error
+
+	// Repanic determines if the recover() value is re-panicked
+	// given the error it has been converted to, instead of
+	// responding with it. Defaults to re-panicking on
+	// [http.ErrAbortHandler] to abort the response, as expected
+	// by [net/http]. Use a function that always returns false
+	// to respond to every panic.
+	Repanic func(err error) bool
+}
+
 // Recover recovers any panics during a [akumu.Handler] execution.
 //
 // If the recover() value is of type error, this is directly passed to
@@ -27,37 +45,115 @@ var (
 // will be used.
 //
 // If none matches, [ErrRecoverUnexpectedError] is returned.
+//
+// Panics with [http.ErrAbortHandler] are re-panicked, as
+// expected by [net/http]. See [RecovererWith] for more details.
 func Recover() akumu.Middleware {
-	return func(handler http.Handler) http.Handler {
-		return RecoverWith(handler, func(value any) error {
-			switch err := (value).(type) {
-			case error:
-				return err
-			case string:
-				return errors.New(err)
-			case fmt.Stringer:
-				return errors.New(err.String())
-			}
-
-			return ErrRecoverUnexpectedError
-		})
-	}
+	return Recoverer(Recovery{})
 }
 
 // RecoverWith allows a handler to decide what to do with the recover() value,
 // allowing to customize the error that [akumu.Failed] receives.
+//
+// See [RecovererWith] for more details.
 func RecoverWith(handler http.Handler, handle func(value any) error) http.Handler {
+	return RecovererWith(handler, Recovery{Handle: handle})
+}
+
+// Recoverer recovers any panics during a [akumu.Handler]
+// execution, using the given [Recovery] options.
+func Recoverer(options Recovery) akumu.Middleware {
+	return func(handler http.Handler) http.Handler {
+		return RecovererWith(handler, options)
+	}
+}
+
+// RecovererWith recovers any panics during a [akumu.Handler] execution
+// but this time accepting the handler as a parameter.
+//
+// The stack of the panicking goroutine is captured and the error is
+// wrapped in an [akumu.ErrPanic], which is always given to the
+// [akumu.OnPanicHook] subscribers, if any. The resulting [akumu.Problem]
+// includes the stack in its errors tree, which is only responded when
+// the [akumu.ProblemControls]'s Errors allows it. Errors that are an
+// [akumu.Responder] are responded as is instead.
+//
+// The stack frames include the function names and the absolute paths
+// of the source files. As [akumu.ProblemControls]'s Errors defaults to
+// true, they are responded to clients unless Errors is disabled or the
+// problem is redacted using [akumu.ProblemControls]'s Redact, which is
+// recommended in production.
+//
+// The recover() value is re-panicked instead when the [Recovery]'s
+// Repanic allows it, which by default aborts the response on
+// [http.ErrAbortHandler], as expected by [net/http].
+func RecovererWith(handler http.Handler, options Recovery) http.Handler {
+	if options.Handle == nil {
+		options.Handle = recoverError
+	}
+
+	if options.Repanic == nil {
+		options.Repanic = recoverAbort
+	}
+
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		defer func() {
-			if err := recover(); err != nil {
+			value := recover()
+
+			if value == nil {
+				return
+			}
+
+			stack := debug.Stack()
+			err := options.Handle(value)
+
+			if options.Repanic(err) {
+				panic(value)
+			}
+
+			panicErr := akumu.ErrPanic{
+				Value:   value,
+				Err:     err,
+				Stack:   akumu.ParseStack(stack),
+				Request: request,
+			}
+
+			akumu.RequestHooks(request).RunPanic(panicErr)
+
+			if _, ok := err.(akumu.Responder); ok {
 				akumu.
-					Failed(handle(err)).
+					Failed(err).
 					Handle(writer, request)
 
 				return
 			}
+
+			akumu.
+				Failed(panicErr).
+				Handle(writer, request)
 		}()
 
 		handler.ServeHTTP(writer, request)
 	})
 }
+
+// recoverError is the default handle function of a [Recovery],
+// converting the recover() value into an error.
+func recoverError(value any) error {
+	switch err := (value).(type) {
+	case error:
+		return err
+	case string:
+		return errors.New(err)
+	case fmt.Stringer:
+		return errors.New(err.String())
+	}
+
+	return ErrRecoverUnexpectedError
+}
+
+// recoverAbort is the default repanic function of a [Recovery],
+// re-panicking on [http.ErrAbortHandler].
+func recoverAbort(err error) bool {
+	return errors.Is(err, http.ErrAbortHandler)
+}
@@ -0,0 +1,175 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+	"github.com/studiolambda/akumu/middleware"
+)
+
+//go:noinline
+func recoverTestPanic(value any) {
+	panic(value)
+}
+
+func panicking(value any) akumu.Handler {
+	return func(request *http.Request) error {
+		recoverTestPanic(value)
+
+		return nil
+	}
+}
+
+func TestRecoverStack(t *testing.T) {
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request.Header.Set("Accept", "application/problem+json")
+
+	handler := middleware.Recover()(panicking("boom"))
+	response := akumu.RecordHandler(handler, request)
+
+	if response.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d but got %d", http.StatusInternalServerError, response.Code)
+	}
+
+	problem := struct {
+		Errors []akumu.ProblemError `json:"errors"`
+	}{}
+
+	if err := json.Unmarshal(response.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+
+	if len(problem.Errors) == 0 || len(problem.Errors[0].Stack) == 0 {
+		t.Fatalf("expected the errors tree to include the stack but got %s", response.Body.String())
+	}
+
+	frame := problem.Errors[0].Stack[0]
+
+	if !strings.HasSuffix(frame.Function, "middleware_test.recoverTestPanic") || !strings.HasSuffix(frame.File, "recover_test.go") {
+		t.Fatalf("expected the first frame to be the panicking function but got %+v", frame)
+	}
+}
+
+func TestRecoverOnPanic(t *testing.T) {
+	panics := make([]akumu.ErrPanic, 0)
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	handler := middleware.Hooks(akumu.Hooks{
+		OnPanic: []akumu.OnPanicHook{func(err akumu.ErrPanic) {
+			panics = append(panics, err)
+		}},
+	})(middleware.Recover()(panicking("boom")))
+
+	akumu.RecordHandler(handler, request)
+
+	if len(panics) != 1 {
+		t.Fatalf("expected 1 panic but got %d", len(panics))
+	}
+
+	if panics[0].Value != "boom" {
+		t.Fatalf("expected panic value boom but got %v", panics[0].Value)
+	}
+
+	if panics[0].Err == nil || panics[0].Err.Error() != "boom" {
+		t.Fatalf("expected panic error boom but got %v", panics[0].Err)
+	}
+
+	if len(panics[0].Stack) == 0 {
+		t.Fatalf("expected panic stack but got none")
+	}
+
+	if panics[0].Request == nil {
+		t.Fatalf("expected panic request but got nil")
+	}
+}
+
+func TestRecoverAbort(t *testing.T) {
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	recovered := func() (value any) {
+		defer func() {
+			value = recover()
+		}()
+
+		akumu.RecordHandler(middleware.Recover()(panicking(http.ErrAbortHandler)), request)
+
+		return nil
+	}()
+
+	if recovered != http.ErrAbortHandler {
+		t.Fatalf("expected panic %v but got %v", http.ErrAbortHandler, recovered)
+	}
+
+	handler := middleware.Recoverer(middleware.Recovery{
+		Repanic: func(err error) bool { return false },
+	})(panicking(http.ErrAbortHandler))
+
+	if response := akumu.RecordHandler(handler, request); response.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d but got %d", http.StatusInternalServerError, response.Code)
+	}
+}
+
+func TestRecoverHandle(t *testing.T) {
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	handler := middleware.RecoverWith(panicking("boom"), func(value any) error {
+		return akumu.Response(http.StatusServiceUnavailable)
+	})
+
+	if response := akumu.RecordHandler(handler, request); response.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d but got %d", http.StatusServiceUnavailable, response.Code)
+	}
+}
+
+func TestRecoverHiddenStack(t *testing.T) {
+	controls := akumu.ProblemControls{
+		Errors: func(akumu.Problem, *http.Request) bool { return false },
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request = request.WithContext(context.WithValue(request.Context(), akumu.ProblemsKey{}, controls))
+	request.Header.Set("Accept", "application/problem+json")
+
+	response := akumu.RecordHandler(middleware.Recover()(panicking(errors.New("boom"))), request)
+	data := make(map[string]any)
+
+	if err := json.Unmarshal(response.Body.Bytes(), &data); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+
+	if _, ok := data["errors"]; ok {
+		t.Fatalf("expected errors to be hidden but got %v", data["errors"])
+	}
+
+	if strings.Contains(response.Body.String(), ".go") {
+		t.Fatalf("expected stack frames to be hidden but got %s", response.Body.String())
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import "net/http"
+
+// responseWriter is an [http.ResponseWriter] that records
+// the status code and the number of bytes of the response.
+//
+// It implements the Unwrap method so that [http.ResponseController]
+// keeps working with the original [http.ResponseWriter].
+type responseWriter struct {
+	http.ResponseWriter
+
+	// status stores the status code of the
+	// response, once the headers are written.
+	status int
+
+	// bytes stores the number of bytes
+	// written in the response body.
+	bytes int64
+}
+
+// WriteHeader records the status code and sends
+// the headers with the given status code.
+func (writer *responseWriter) WriteHeader(status int) {
+	if writer.status == 0 && status >= http.StatusOK {
+		writer.status = status
+	}
+
+	writer.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of bytes written
+// and writes them in the response body.
+func (writer *responseWriter) Write(body []byte) (int, error) {
+	if writer.status == 0 {
+		writer.status = http.StatusOK
+	}
+
+	written, err := writer.ResponseWriter.Write(body)
+	writer.bytes += int64(written)
+
+	return written, err
+}
+
+// Flush implements the [http.Flusher] interface, flushing
+// the original [http.ResponseWriter] if it supports it.
+func (writer *responseWriter) Flush() {
+	_ = http.NewResponseController(writer.ResponseWriter).Flush()
+}
+
+// Unwrap returns the original [http.ResponseWriter].
+func (writer *responseWriter) Unwrap() http.ResponseWriter {
+	return writer.ResponseWriter
+}
+
+// Status returns the recorded status code, which
+// is [http.StatusOK] if none has been written.
+func (writer *responseWriter) Status() int {
+	if writer.status == 0 {
+		return http.StatusOK
+	}
+
+	return writer.status
+}
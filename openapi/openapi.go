@@ -0,0 +1,338 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/studiolambda/akumu"
+)
+
+// Version is the OpenAPI specification
+// version of the generated documents.
+const Version = "3.1.0"
+
+// Info provides metadata about the API.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Summary     string `json:"summary,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Document is the root object of an OpenAPI document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// PathItem describes the operations available on a single
+// path, keyed by the lowercased http method.
+type PathItem map[string]OperationObject
+
+// OperationObject describes a single API operation on a path.
+type OperationObject struct {
+	OperationID string              `json:"operationId,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Deprecated  bool                `json:"deprecated,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter describes a single operation parameter.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody describes a single request body.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a single response from an API operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType provides the schema for a given media type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the reusable objects of the document.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Operation is the metadata that can be attached to an
+// [akumu.Route] using [Spec.Describe] to document it.
+type Operation struct {
+
+	// ID is the unique identifier of the operation. If empty,
+	// the route name is used instead (if any).
+	ID string
+
+	// Summary is a short summary of what the operation does.
+	Summary string
+
+	// Description is a verbose explanation of the operation.
+	Description string
+
+	// Tags are used to group operations together.
+	Tags []string
+
+	// Deprecated declares the operation to be deprecated.
+	Deprecated bool
+
+	// Request is a value of the type that the request
+	// body is decoded to. It's described as "application/json".
+	//
+	// A nil value means the operation has no request body.
+	Request any
+
+	// Responses maps status codes to a value of the type
+	// of the response body. It's described as "application/json"
+	// unless the value is an [akumu.Problem], in which case it's
+	// described as "application/problem+json".
+	//
+	// A nil value means the response has no body.
+	Responses map[int]any
+}
+
+// key identifies a route by its method and pattern.
+type key struct {
+	method  string
+	pattern string
+}
+
+// Spec stores the [Operation] metadata of the routes and
+// generates the OpenAPI [Document] of an [akumu.Router].
+type Spec struct {
+	info       Info
+	operations map[key]Operation
+}
+
+// New creates a new [Spec] with the given API [Info].
+func New(info Info) *Spec {
+	return &Spec{
+		info:       info,
+		operations: make(map[key]Operation),
+	}
+}
+
+// Describe attaches the given [Operation] to the route.
+//
+// The route is returned as-is so that it can
+// be used when registering it, for example:
+//
+//	spec.Describe(router.Get("/users/{user}", show), openapi.Operation{
+//		Summary:   "Shows a user",
+//		Responses: map[int]any{http.StatusOK: User{}},
+//	})
+func (spec *Spec) Describe(route *akumu.Route, operation Operation) *akumu.Route {
+	spec.operations[key{route.Method(), route.Pattern()}] = operation
+
+	return route
+}
+
+// Document generates the OpenAPI [Document] of all the routes
+// registered in the given [akumu.Router].
+//
+// Routes that are not described still appear in the document
+// with their path parameters and the default problem response.
+// Trailing slash redirection routes and methods that cannot be
+// described by OpenAPI, such as CONNECT, are left out.
+func (spec *Spec) Document(router *akumu.Router) Document {
+	generator := schemas{
+		components: map[string]*Schema{
+			"Problem": problemSchema(),
+		},
+		names: make(map[reflect.Type]string),
+	}
+
+	document := Document{
+		OpenAPI: Version,
+		Info:    spec.info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas: generator.components,
+		},
+	}
+
+	routes := slices.DeleteFunc(router.Routes(), func(route akumu.RouteInfo) bool {
+		return route.Redirect || route.Method == http.MethodConnect
+	})
+
+	names := make(map[string]int)
+
+	for _, route := range routes {
+		names[route.Name]++
+	}
+
+	for _, route := range routes {
+
+		path, parameters := convert(route.Pattern)
+		operation := spec.operations[key{route.Method, route.Pattern}]
+
+		if _, ok := document.Paths[path]; !ok {
+			document.Paths[path] = make(PathItem)
+		}
+
+		object := OperationObject{
+			OperationID: operation.ID,
+			Summary:     operation.Summary,
+			Description: operation.Description,
+			Tags:        operation.Tags,
+			Deprecated:  operation.Deprecated,
+			Parameters:  parameters,
+			Responses: map[string]Response{
+				"default": problemResponse("Problem details describing the error."),
+			},
+		}
+
+		if object.OperationID == "" {
+			object.OperationID = operationID(route, names[route.Name])
+		}
+
+		if operation.Request != nil {
+			object.RequestBody = &RequestBody{
+				Required: true,
+				Content: map[string]MediaType{
+					"application/json": {Schema: generator.of(operation.Request)},
+				},
+			}
+		}
+
+		statuses := make([]int, 0, len(operation.Responses))
+
+		for status := range operation.Responses {
+			statuses = append(statuses, status)
+		}
+
+		// Responses are described in order so that the component
+		// names of types sharing the same name are deterministic.
+		slices.Sort(statuses)
+
+		for _, status := range statuses {
+			object.Responses[fmt.Sprint(status)] = response(generator, status, operation.Responses[status])
+		}
+
+		document.Paths[path][strings.ToLower(route.Method)] = object
+	}
+
+	return document
+}
+
+// Handler returns an [akumu.Handler] that serves the OpenAPI
+// [Document] of the given [akumu.Router] as JSON.
+//
+// The document is generated on each request, meaning that routes
+// registered after this call are also documented. It can be mounted
+// at any path, for example:
+//
+//	router.Get("/openapi.json", spec.Handler(router))
+func (spec *Spec) Handler(router *akumu.Router) akumu.Handler {
+	return func(request *http.Request) error {
+		return akumu.
+			Response(http.StatusOK).
+			JSON(spec.Document(router))
+	}
+}
+
+// convert transforms an [http.ServeMux] pattern into an OpenAPI
+// path and returns the path parameters found on it.
+//
+// Wildcards such as "{name...}" are converted to "{name}"
+// and the "{$}" anchor is removed.
+func convert(pattern string) (string, []Parameter) {
+	segments := strings.Split(pattern, "/")
+	parameters := make([]Parameter, 0)
+
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+
+		if name == "$" {
+			segments[i] = ""
+			continue
+		}
+
+		name = strings.TrimSuffix(name, "...")
+		segments[i] = "{" + name + "}"
+		parameters = append(parameters, Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	return strings.Join(segments, "/"), parameters
+}
+
+// operationID returns the operation id of the given route from its name,
+// given the number of routes sharing it. Names shared by several routes,
+// such as the ones given to [akumu.Routes], are suffixed with the
+// lowercased method so that operation ids stay unique.
+func operationID(route akumu.RouteInfo, shared int) string {
+	if route.Name == "" || shared <= 1 {
+		return route.Name
+	}
+
+	return route.Name + "." + strings.ToLower(route.Method)
+}
+
+// description returns the description of the response with the
+// given status, which is required even for non-standard codes.
+func description(status int) string {
+	if text := http.StatusText(status); text != "" {
+		return text
+	}
+
+	return fmt.Sprintf("Status %d", status)
+}
+
+// response returns the [Response] that describes the given body.
+func response(generator schemas, status int, body any) Response {
+	switch body.(type) {
+	case nil:
+		return Response{Description: description(status)}
+	case akumu.Problem, *akumu.Problem:
+		return problemResponse(description(status))
+	}
+
+	return Response{
+		Description: description(status),
+		Content: map[string]MediaType{
+			"application/json": {Schema: generator.of(body)},
+		},
+	}
+}
+
+// problemResponse returns a [Response] described by the
+// "application/problem+json" media type and problem schema.
+func problemResponse(description string) Response {
+	return Response{
+		Description: description,
+		Content: map[string]MediaType{
+			"application/problem+json": {
+				Schema: &Schema{Ref: "#/components/schemas/Problem"},
+			},
+		},
+	}
+}
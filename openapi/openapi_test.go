@@ -0,0 +1,246 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+	"github.com/studiolambda/akumu/openapi"
+)
+
+type User struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	Email    string   `json:"email,omitempty"`
+	Friends  []User   `json:"friends,omitempty"`
+	Internal string   `json:"-"`
+	Tags     []string `json:"tags"`
+}
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+}
+
+type URL struct {
+	Address string `json:"address"`
+}
+
+type Problem struct {
+	Reason string `json:"reason"`
+}
+
+func ok(request *http.Request) error {
+	return akumu.Response(http.StatusOK)
+}
+
+func TestDocument(t *testing.T) {
+	router := akumu.NewRouter()
+	spec := openapi.New(openapi.Info{Title: "Users", Version: "1.0.0"})
+
+	router.Group("/users", func(router *akumu.Router) {
+		spec.Describe(router.Get("/{user}", ok).Name("users.show"), openapi.Operation{
+			Summary: "Shows a user",
+			Responses: map[int]any{
+				http.StatusOK:       User{},
+				http.StatusNotFound: akumu.Problem{},
+			},
+		})
+
+		spec.Describe(router.Post("/", ok), openapi.Operation{
+			ID:      "users.create",
+			Request: User{},
+			Responses: map[int]any{
+				http.StatusCreated: nil,
+			},
+		})
+
+		router.Get("/{user}/files/{path...}", ok)
+	})
+
+	document := spec.Document(router)
+
+	if expected := openapi.Version; document.OpenAPI != expected {
+		t.Fatalf("expected openapi version %s but got %s", expected, document.OpenAPI)
+	}
+
+	show, found := document.Paths["/users/{user}"]["get"]
+
+	if !found {
+		t.Fatalf("expected the /users/{user} get operation to be documented")
+	}
+
+	if expected := "users.show"; show.OperationID != expected {
+		t.Fatalf("expected operation id %s but got %s", expected, show.OperationID)
+	}
+
+	if len(show.Parameters) != 1 || show.Parameters[0].Name != "user" || show.Parameters[0].In != "path" {
+		t.Fatalf("expected a single user path parameter but got %+v", show.Parameters)
+	}
+
+	if expected := "#/components/schemas/User"; show.Responses["200"].Content["application/json"].Schema.Ref != expected {
+		t.Fatalf("expected the 200 response to reference %s", expected)
+	}
+
+	if _, found := show.Responses["404"].Content["application/problem+json"]; !found {
+		t.Fatalf("expected the 404 response to be a problem")
+	}
+
+	if _, found := show.Responses["default"].Content["application/problem+json"]; !found {
+		t.Fatalf("expected the default response to be a problem")
+	}
+
+	create, found := document.Paths["/users"]["post"]
+
+	if !found {
+		t.Fatalf("expected the /users post operation to be documented")
+	}
+
+	if create.RequestBody == nil {
+		t.Fatalf("expected the /users post operation to have a request body")
+	}
+
+	files, found := document.Paths["/users/{user}/files/{path}"]["get"]
+
+	if !found {
+		t.Fatalf("expected the /users/{user}/files/{path} get operation to be documented")
+	}
+
+	if expected := 2; len(files.Parameters) != expected {
+		t.Fatalf("expected %d parameters but got %d", expected, len(files.Parameters))
+	}
+
+	user, found := document.Components.Schemas["User"]
+
+	if !found {
+		t.Fatalf("expected the User schema to be a component")
+	}
+
+	if _, found := user.Properties["Internal"]; found {
+		t.Fatalf("expected ignored fields to not be in the schema")
+	}
+
+	if expected := []string{"id", "name", "tags"}; len(user.Required) != len(expected) {
+		t.Fatalf("expected required properties %v but got %v", expected, user.Required)
+	}
+
+	if expected := "#/components/schemas/User"; user.Properties["friends"].Items.Ref != expected {
+		t.Fatalf("expected self references to reference %s", expected)
+	}
+}
+
+func TestDocumentComponentNames(t *testing.T) {
+	router := akumu.NewRouter()
+	spec := openapi.New(openapi.Info{Title: "Names", Version: "1.0.0"})
+
+	spec.Describe(router.Get("/users", ok), openapi.Operation{
+		Responses: map[int]any{http.StatusOK: Page[User]{}},
+	})
+
+	spec.Describe(router.Get("/links", ok), openapi.Operation{
+		Responses: map[int]any{http.StatusOK: URL{}, http.StatusAccepted: url.URL{}},
+	})
+
+	spec.Describe(router.Get("/problems", ok), openapi.Operation{
+		Responses: map[int]any{http.StatusOK: Problem{}},
+	})
+
+	document := spec.Document(router)
+	pattern := regexp.MustCompile(`^[a-zA-Z0-9.\-_]+$`)
+
+	for name := range document.Components.Schemas {
+		if !pattern.MatchString(name) {
+			t.Fatalf("expected component name %s to be valid", name)
+		}
+	}
+
+	users := document.Paths["/users"]["get"].Responses["200"].Content["application/json"].Schema.Ref
+
+	if expected := "#/components/schemas/Page_openapi_test.User"; users != expected {
+		t.Fatalf("expected the generic type to reference %s but got %s", expected, users)
+	}
+
+	links := document.Paths["/links"]["get"].Responses
+
+	if expected := "#/components/schemas/URL"; links["200"].Content["application/json"].Schema.Ref != expected {
+		t.Fatalf("expected the first type to reference %s", expected)
+	}
+
+	if expected := "#/components/schemas/net.url.URL"; links["202"].Content["application/json"].Schema.Ref != expected {
+		t.Fatalf("expected the type with the same name to reference %s", expected)
+	}
+
+	problems := document.Paths["/problems"]["get"].Responses["200"].Content["application/json"].Schema.Ref
+
+	if !strings.HasSuffix(problems, "openapi_test.Problem") {
+		t.Fatalf("expected the Problem type to be qualified but got %s", problems)
+	}
+
+	if _, found := document.Components.Schemas["Problem"].Properties["reason"]; found {
+		t.Fatalf("expected the Problem component to not be overwritten")
+	}
+}
+
+func TestDocumentSharedNames(t *testing.T) {
+	router := akumu.NewRouter()
+	spec := openapi.New(openapi.Info{Title: "Names", Version: "1.0.0"})
+
+	router.Methods([]string{http.MethodGet, http.MethodPost}, "/users", ok).Name("users")
+
+	spec.Describe(router.Get("/teapot", ok).Name("teapot"), openapi.Operation{
+		Responses: map[int]any{299: nil},
+	})
+
+	document := spec.Document(router)
+
+	if id := document.Paths["/users"]["get"].OperationID; id != "users.get" {
+		t.Fatalf("expected operation id users.get but got %s", id)
+	}
+
+	if id := document.Paths["/users"]["post"].OperationID; id != "users.post" {
+		t.Fatalf("expected operation id users.post but got %s", id)
+	}
+
+	teapot := document.Paths["/teapot"]["get"]
+
+	if teapot.OperationID != "teapot" {
+		t.Fatalf("expected operation id teapot but got %s", teapot.OperationID)
+	}
+
+	if expected := "Status 299"; teapot.Responses["299"].Description != expected {
+		t.Fatalf("expected description %s but got %s", expected, teapot.Responses["299"].Description)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	router := akumu.NewRouter()
+	spec := openapi.New(openapi.Info{Title: "Users", Version: "1.0.0"})
+
+	router.Get("/openapi.json", spec.Handler(router))
+	router.Get("/users", ok)
+
+	request, err := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	response := router.Record(request)
+
+	if expected := http.StatusOK; response.Code != expected {
+		t.Fatalf("expected status code %d but got %d", expected, response.Code)
+	}
+
+	var document openapi.Document
+
+	if err := json.NewDecoder(response.Body).Decode(&document); err != nil {
+		t.Fatalf("failed to decode document: %v", err)
+	}
+
+	if _, found := document.Paths["/users"]["get"]; !found {
+		t.Fatalf("expected the /users get operation to be documented")
+	}
+}
@@ -0,0 +1,230 @@
+package openapi
+
+import (
+	"encoding"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema represents a JSON Schema object as used
+// by OpenAPI 3.1 documents.
+//
+// Only the subset of JSON Schema that's needed to
+// describe Go types is represented.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+}
+
+var (
+	// timeType is the [reflect.Type] of [time.Time].
+	timeType = reflect.TypeFor[time.Time]()
+
+	// textMarshalerType is the [reflect.Type] of [encoding.TextMarshaler].
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+	// componentPackage matches the package paths of
+	// the type arguments in the name of generic types.
+	componentPackage = regexp.MustCompile(`[^\[\],/]+/`)
+
+	// componentInvalid matches the characters that are not allowed
+	// in the key of an OpenAPI component, as defined by the pattern
+	// ^[a-zA-Z0-9.\-_]+$ of the specification.
+	componentInvalid = regexp.MustCompile(`[^a-zA-Z0-9.\-_]+`)
+)
+
+// problemSchema returns the [Schema] of an RFC 9457 problem details
+// object, as it's serialized by [akumu.Problem].
+func problemSchema() *Schema {
+	return &Schema{
+		Type:        "object",
+		Description: "Problem details for HTTP APIs (RFC 9457).",
+		Properties: map[string]*Schema{
+			"type":     {Type: "string", Format: "uri-reference"},
+			"title":    {Type: "string"},
+			"detail":   {Type: "string"},
+			"status":   {Type: "integer"},
+			"instance": {Type: "string", Format: "uri-reference"},
+		},
+		AdditionalProperties: true,
+	}
+}
+
+// schemas generates [Schema] from go types, storing the named
+// struct types in the given components so they can be referenced.
+type schemas struct {
+	components map[string]*Schema
+
+	// names stores the component name of
+	// each of the named struct types.
+	names map[reflect.Type]string
+}
+
+// of returns the [Schema] that describes the type of the given value.
+func (generator schemas) of(value any) *Schema {
+	return generator.schema(reflect.TypeOf(value))
+}
+
+// schema returns the [Schema] that describes the given type.
+//
+// Named struct types are stored as components and a
+// reference to them is returned instead.
+func (generator schemas) schema(typ reflect.Type) *Schema {
+	if typ == nil {
+		return &Schema{}
+	}
+
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	if typ.Implements(textMarshalerType) || reflect.PointerTo(typ).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: generator.schema(typ.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: generator.schema(typ.Elem())}
+	case reflect.Struct:
+		if typ.Name() == "" {
+			return generator.object(typ)
+		}
+
+		name, found := generator.names[typ]
+
+		if !found {
+			name = generator.name(typ)
+			generator.names[typ] = name
+
+			// The placeholder prevents infinite recursion
+			// on self-referencing types.
+			generator.components[name] = &Schema{}
+			*generator.components[name] = *generator.object(typ)
+		}
+
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	return &Schema{}
+}
+
+// name returns an unused component name for the given named type.
+//
+// The name of the type is used, without the package paths of its
+// type arguments, if any. When it's already used by another type,
+// the name is qualified with the package path of the type.
+func (generator schemas) name(typ reflect.Type) string {
+	short := componentPackage.ReplaceAllString(typ.Name(), "")
+	name := componentName(short)
+
+	if _, taken := generator.components[name]; !taken {
+		return name
+	}
+
+	qualified := componentName(strings.ReplaceAll(typ.PkgPath(), "/", ".") + "." + short)
+	name = qualified
+
+	for i := 2; ; i++ {
+		if _, taken := generator.components[name]; !taken {
+			return name
+		}
+
+		name = qualified + "_" + strconv.Itoa(i)
+	}
+}
+
+// componentName returns the given name with the characters that are
+// not allowed in the key of an OpenAPI component replaced by "_".
+func componentName(name string) string {
+	return strings.Trim(componentInvalid.ReplaceAllString(name, "_"), "_")
+}
+
+// object returns the [Schema] of the given struct type by
+// following the same rules as the [json] package does.
+func (generator schemas) object(typ reflect.Type) *Schema {
+	schema := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				inner := generator.object(embedded)
+
+				for key, value := range inner.Properties {
+					schema.Properties[key] = value
+				}
+
+				schema.Required = append(schema.Required, inner.Required...)
+
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = generator.schema(field.Type)
+
+		if !strings.Contains(options, "omitempty") && !strings.Contains(options, "omitzero") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
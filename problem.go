@@ -1,9 +1,9 @@
 package akumu
 
 import (
-	_ "embed"
 	"encoding/json"
 	"fmt"
+	"html/template"
 	"maps"
 	"net/http"
 	"strings"
@@ -139,6 +139,11 @@ type ProblemControls struct {
 
 	// Errors determines if the problem should contain a stack-trace
 	// of errors from the error it comes from (if any).
+	//
+	// It defaults to true, so the stack frames of errors that are a
+	// [StackTracer], such as [ErrPanic], are responded as well, including
+	// function names and absolute file paths. Disable it or use Redact
+	// in production.
 	Errors ProblemControlsResolver[bool]
 
 	// ErrorsKey determines the key to use when appending the
@@ -148,6 +153,50 @@ type ProblemControls struct {
 	// Response allows customizing the actual Builder response
 	// that a [Problem] should be resolved to.
 	Response ProblemControlsResolver[Builder]
+
+	// Catalog determines the [ProblemCatalog] used to localize
+	// the title and detail of a [Problem]. Use [ProblemControlsCatalog]
+	// to always use the same catalog.
+	//
+	// Localized titles and details replace the ones of the [Problem]
+	// that still hold their default value, unless the [ProblemMessage]
+	// overrides them, and responses get the Content-Language and Vary
+	// headers set accordingly.
+	Catalog ProblemControlsResolver[ProblemCatalog]
+
+	// Template determines the template used to render the HTML
+	// representation of a [Problem], given a [ProblemPage]. Use
+	// [ProblemControlsTemplate] to use templates per problem
+	// type or status.
+	Template ProblemControlsResolver[*template.Template]
+
+	// Redact determines if server error problems, those with a 5xx status,
+	// should be redacted. Redacted problems replace their detail with the
+	// RedactedDetails, never include the errors trace and include an
+	// opaque Occurrence identifier in the "occurrence" member.
+	//
+	// The full error chain is given to the [OnErrorHook] subscribers as an
+	// [ErrRedacted], or logged using [log/slog.Default] if there's none,
+	// keyed by that identifier.
+	Redact ProblemControlsResolver[bool]
+
+	// RedactedDetails determines the generic details
+	// of a [Problem] that has been redacted.
+	RedactedDetails ProblemControlsResolver[string]
+
+	// Occurrence determines the opaque identifier of
+	// the occurrence of a [Problem] that has been redacted.
+	Occurrence ProblemControlsResolver[string]
+
+	// Language determines the language tag used to localize a [Problem]
+	// using the Catalog. By default, it's selected from the catalog
+	// languages using the request's Accept-Language header.
+	Language ProblemControlsResolver[string]
+
+	// Registry determines the [ProblemRegistry] used to map the errors
+	// that are not a [Responder] into a [Problem]. Use [ProblemControlsRegistry]
+	// to always use the same registry. By default, it's the [DefaultProblemRegistry].
+	Registry ProblemControlsResolver[*ProblemRegistry]
 }
 
 // ProblemsKey is the context key where the
@@ -193,6 +242,34 @@ func defaultedProblemControls(controls ProblemControls) ProblemControls {
 		controls.Response = defaultProblemControlsResponse
 	}
 
+	if controls.Catalog == nil {
+		controls.Catalog = defaultProblemControlsCatalog
+	}
+
+	if controls.Redact == nil {
+		controls.Redact = defaultProblemControlsRedact
+	}
+
+	if controls.RedactedDetails == nil {
+		controls.RedactedDetails = defaultProblemControlsRedactedDetails
+	}
+
+	if controls.Occurrence == nil {
+		controls.Occurrence = defaultProblemControlsOccurrence
+	}
+
+	if controls.Template == nil {
+		controls.Template = defaultProblemControlsTemplate
+	}
+
+	if controls.Language == nil {
+		controls.Language = defaultProblemControlsLanguage
+	}
+
+	if controls.Registry == nil {
+		controls.Registry = defaultProblemControlsRegistry
+	}
+
 	return controls
 }
 
@@ -234,7 +311,7 @@ func defaultProblemControlsInstance(problem Problem, request *http.Request) stri
 // defaultProblemControlsDetails is the default value for the [ProblemControls] instance.
 func defaultProblemControlsDetails(problem Problem, request *http.Request) string {
 	if traces := problem.Errors(); len(traces) > 0 {
-		return traces[0].Error()
+		return traces[0].Message
 	}
 
 	return ""
@@ -269,16 +346,20 @@ func ProblemControlsResponseFrom(responses map[string]Builder) ProblemControlsRe
 			problem.Detail,
 		)
 
+		if occurrence, ok := problem.Additional("occurrence"); ok {
+			textResponse += fmt.Sprintf("\n\nOccurrence: %v", occurrence)
+		}
+
 		controls := problem.controls(request)
 
 		errors, found := problem.Additional(controls.ErrorsKey(problem, request))
-		traces, tracesOK := errors.([]string)
+		traces, tracesOK := errors.([]ProblemError)
 
 		if found && tracesOK && controls.Errors(problem, request) {
 			textResponse += fmt.Sprintf("\n\n")
 
-			for _, trace := range traces {
-				textResponse += fmt.Sprintf("%s\n", trace)
+			for _, line := range errorLines(traces) {
+				textResponse += fmt.Sprintf("%s\n", line)
 			}
 		}
 
@@ -290,13 +371,18 @@ func ProblemControlsResponseFrom(responses map[string]Builder) ProblemControlsRe
 // defaultProblemControlsResponse is the default response that will be
 // used on the [ProblemControls]
 func defaultProblemControlsResponse(problem Problem, request *http.Request) Builder {
+	jsonResponse := Response(problem.Status).
+		JSON(problem).
+		Header("Content-Type", "application/problem+json")
+
+	xmlResponse := problemXMLResponse(problem)
+
 	responses := map[string]Builder{
-		"application/problem+json": Response(problem.Status).
-			JSON(problem).
-			Header("Content-Type", "application/problem+json"),
-		"application/json": Response(problem.Status).
-			JSON(problem).
-			Header("Content-Type", "application/problem+json"),
+		"application/problem+json": jsonResponse,
+		"application/json":         jsonResponse,
+		"application/problem+xml":  xmlResponse,
+		"application/xml":          xmlResponse,
+		"text/html":                problemHTMLResponse(problem, request),
 	}
 
 	return ProblemControlsResponseFrom(responses)(problem, request)
@@ -372,10 +458,10 @@ func (problem Problem) Error() string {
 	return fmt.Sprintf("%d %s: %s", problem.Status, http.StatusText(problem.Status), problem.Title)
 }
 
-// Errors returns all the strack-trace of errors that
-// are bound to this particular [Problem].
-func (problem Problem) Errors() []error {
-	return stackTrace(problem.err)
+// Errors returns the tree of errors that are bound to this
+// particular [Problem], including the errors they wrap.
+func (problem Problem) Errors() []ProblemError {
+	return errorTree(problem.err, 0)
 }
 
 // Unwrap is used to get the original error from
@@ -456,8 +542,15 @@ func (problem Problem) controls(request *http.Request) ProblemControls {
 // Defaulted returns a [Problem] that is defaulted using the given
 // request and the current instance.
 func (problem Problem) Defaulted(request *http.Request) Problem {
-	controls := problem.controls(request)
+	problem, _, _ = problem.defaulted(request, problem.controls(request))
 
+	return problem
+}
+
+// defaulted returns the [Problem] defaulted using the given request and
+// [ProblemControls], along with the language it was localized to, if any,
+// and the [ErrRedacted] to report when it has been redacted.
+func (problem Problem) defaulted(request *http.Request, controls ProblemControls) (Problem, string, *ErrRedacted) {
 	if problem.Type == "" {
 		problem.Type = controls.DefaultType(problem, request)
 	}
@@ -466,6 +559,8 @@ func (problem Problem) Defaulted(request *http.Request) Problem {
 		problem.Status = controls.DefaultStatus(problem, request)
 	}
 
+	problem, language := problem.localized(request, controls)
+
 	if problem.Title == "" {
 		problem.Title = controls.DefaultTitle(problem, request)
 	}
@@ -480,22 +575,25 @@ func (problem Problem) Defaulted(request *http.Request) Problem {
 
 	lower := controls.Lowercase(problem, request)
 
-	if controls.Errors(problem, request) {
+	var redacted *ErrRedacted
+
+	if problem.redacts(request, controls) {
+		var err ErrRedacted
+
+		problem, err = problem.redacted(request, controls)
+		redacted = &err
+	} else if controls.Errors(problem, request) {
 		traces := problem.Errors()
-		messages := make([]string, len(traces))
 
-		for i, trace := range traces {
-			if lower {
-				messages[i] = strings.ToLower(trace.Error())
-				continue
+		if lower {
+			for i, trace := range traces {
+				traces[i] = trace.lowercased()
 			}
-
-			messages[i] = trace.Error()
 		}
 
 		problem = problem.With(
 			controls.ErrorsKey(problem, request),
-			messages,
+			traces,
 		)
 	}
 
@@ -504,13 +602,30 @@ func (problem Problem) Defaulted(request *http.Request) Problem {
 		problem.Detail = strings.ToLower(problem.Detail)
 	}
 
-	return problem
+	return problem, language, redacted
 }
 
 // Respond implements [Responder] interface to implement
 // how a problem responds to an http request.
+//
+// Localized problems also set the Content-Language header
+// and append "Accept-Language" to the Vary header.
+//
+// Redacted problems are reported once the response is handled,
+// instead of the [ErrServer] of the response.
 func (problem Problem) Respond(request *http.Request) Builder {
 	controls := problem.controls(request)
+	defaulted, language, redacted := problem.defaulted(request, controls)
+	response := controls.Response(defaulted, request)
+	response.redacted = redacted
+
+	if len(controls.Catalog(defaulted, request).Languages) == 0 {
+		return response
+	}
+
+	if language != "" {
+		response = response.Header("Content-Language", language)
+	}
 
-	return controls.Response(problem.Defaulted(request), request)
+	return response.AppendHeader("Vary", "Accept-Language")
 }
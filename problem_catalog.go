@@ -0,0 +1,170 @@
+package akumu
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/studiolambda/akumu/utils"
+)
+
+// ProblemMessage is a localized title and
+// detail of a [Problem].
+//
+// By default, only the title and detail that still hold their
+// default value are localized, so that the ones explicitly set
+// on the [Problem] are kept.
+type ProblemMessage struct {
+
+	// Title is the localized title of the [Problem].
+	// It's left untouched when empty.
+	Title string
+
+	// Detail is the localized detail of the [Problem].
+	// It's left untouched when empty.
+	Detail string
+
+	// Override determines if the title and detail replace
+	// the ones explicitly set on the [Problem].
+	Override bool
+}
+
+// ProblemTranslations stores the [ProblemMessage] of
+// a single language, keyed by problem type and status.
+type ProblemTranslations struct {
+
+	// Types stores the messages keyed by the problem type.
+	// They take precedence over the messages of Statuses.
+	Types map[string]ProblemMessage
+
+	// Statuses stores the messages keyed by the problem status.
+	Statuses map[int]ProblemMessage
+}
+
+// ProblemCatalog is a message catalog that localizes
+// the title and detail of a [Problem] based on the
+// request's Accept-Language header.
+type ProblemCatalog struct {
+
+	// Fallback is the language used when none of the
+	// catalog languages are acceptable, or when the
+	// selected language lacks a translation.
+	Fallback string
+
+	// Languages stores the translations
+	// keyed by their language tag.
+	Languages map[string]ProblemTranslations
+}
+
+// ProblemControlsCatalog is a helper that generates a
+// [ProblemControlsResolver] that always resolves to the
+// given [ProblemCatalog].
+func ProblemControlsCatalog(catalog ProblemCatalog) ProblemControlsResolver[ProblemCatalog] {
+	return func(problem Problem, request *http.Request) ProblemCatalog {
+		return catalog
+	}
+}
+
+// defaultProblemControlsCatalog is the default value for the [ProblemControls] catalog.
+func defaultProblemControlsCatalog(problem Problem, request *http.Request) ProblemCatalog {
+	return ProblemCatalog{}
+}
+
+// defaultProblemControlsLanguage is the default value for the [ProblemControls] language.
+//
+// It uses the lookup of RFC 4647 on the catalog languages, using the
+// catalog fallback when there's no Accept-Language header or no match.
+func defaultProblemControlsLanguage(problem Problem, request *http.Request) string {
+	catalog := problem.controls(request).Catalog(problem, request)
+
+	return utils.
+		ParseAcceptLanguage(request).
+		Lookup(catalog.Tags(), catalog.Fallback)
+}
+
+// Tags returns the language tags of the catalog, sorted,
+// with the fallback language always being the first one.
+func (catalog ProblemCatalog) Tags() []string {
+	tags := make([]string, 0, len(catalog.Languages))
+
+	for tag := range catalog.Languages {
+		if tag != catalog.Fallback {
+			tags = append(tags, tag)
+		}
+	}
+
+	slices.Sort(tags)
+
+	if _, ok := catalog.Languages[catalog.Fallback]; ok {
+		tags = slices.Insert(tags, 0, catalog.Fallback)
+	}
+
+	return tags
+}
+
+// translation returns the [ProblemMessage] of the given
+// problem in the given language, if any.
+func (catalog ProblemCatalog) translation(language string, problem Problem) (ProblemMessage, bool) {
+	translations, ok := catalog.Languages[language]
+
+	if !ok {
+		return ProblemMessage{}, false
+	}
+
+	if message, ok := translations.Types[problem.Type]; ok && problem.Type != "" {
+		return message, true
+	}
+
+	message, ok := translations.Statuses[problem.Status]
+
+	return message, ok
+}
+
+// Message returns the [ProblemMessage] of the given problem
+// in the given language, falling back to the catalog's fallback
+// language when the translation is missing.
+//
+// The second return value is the language of the message, or an
+// empty string if there's no translation in either language.
+func (catalog ProblemCatalog) Message(language string, problem Problem) (ProblemMessage, string) {
+	if message, ok := catalog.translation(language, problem); ok {
+		return message, language
+	}
+
+	if message, ok := catalog.translation(catalog.Fallback, problem); ok {
+		return message, catalog.Fallback
+	}
+
+	return ProblemMessage{}, ""
+}
+
+// localized returns the problem with its title and detail localized
+// using the [ProblemControls] catalog, and the language used, if any.
+//
+// Only the title and detail that still hold their default value are
+// localized, unless the [ProblemMessage] overrides them.
+func (problem Problem) localized(request *http.Request, controls ProblemControls) (Problem, string) {
+	catalog := controls.Catalog(problem, request)
+
+	if len(catalog.Languages) == 0 {
+		return problem, ""
+	}
+
+	message, language := catalog.Message(controls.Language(problem, request), problem)
+	localized := false
+
+	if message.Title != "" && (message.Override || problem.Title == "" || problem.Title == controls.DefaultTitle(problem, request)) {
+		problem.Title = message.Title
+		localized = true
+	}
+
+	if message.Detail != "" && (message.Override || problem.Detail == "" || problem.Detail == controls.DefaultDetails(problem, request)) {
+		problem.Detail = message.Detail
+		localized = true
+	}
+
+	if !localized {
+		return problem, ""
+	}
+
+	return problem, language
+}
@@ -0,0 +1,112 @@
+package akumu_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+)
+
+var problemTestCatalog = akumu.ProblemCatalog{
+	Fallback: "en",
+	Languages: map[string]akumu.ProblemTranslations{
+		"en": {
+			Types: map[string]akumu.ProblemMessage{
+				"http://example.com/problems/unauthenticated": {Title: "Unauthenticated", Override: true},
+			},
+			Statuses: map[int]akumu.ProblemMessage{
+				http.StatusNotFound: {Title: "Not Found", Detail: "The resource does not exist."},
+			},
+		},
+		"es": {
+			Types: map[string]akumu.ProblemMessage{
+				"http://example.com/problems/unauthenticated": {Title: "No autenticado", Override: true},
+			},
+			Statuses: map[int]akumu.ProblemMessage{
+				http.StatusNotFound: {Title: "No encontrado", Detail: "El recurso no existe."},
+			},
+		},
+	},
+}
+
+func TestProblemCatalog(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		problem  akumu.Problem
+		title    string
+		detail   string
+		content  string
+	}{
+		{"status", "es-ES, en;q=0.5", akumu.NewProblem(nil, http.StatusNotFound), "No encontrado", "El recurso no existe.", "es"},
+		{"type", "es", ErrNotAuthenticated, "No autenticado", ErrNotAuthenticated.Detail, "es"},
+		{"no header", "", akumu.NewProblem(nil, http.StatusNotFound), "Not Found", "The resource does not exist.", "en"},
+		{"unknown language", "ja", akumu.NewProblem(nil, http.StatusNotFound), "Not Found", "The resource does not exist.", "en"},
+		{"explicit", "es", akumu.Problem{Status: http.StatusNotFound, Title: "Missing", Detail: "Custom detail."}, "Missing", "Custom detail.", ""},
+		{"explicit title", "es", akumu.Problem{Status: http.StatusNotFound, Title: "Missing"}, "Missing", "El recurso no existe.", "es"},
+		{"missing translation", "es", akumu.NewProblem(nil, http.StatusConflict), "Conflict", "", ""},
+	}
+
+	controls := akumu.ProblemControls{
+		Lowercase: func(akumu.Problem, *http.Request) bool { return false },
+		Catalog:   akumu.ProblemControlsCatalog(problemTestCatalog),
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+			if err != nil {
+				t.Fatalf("failed to create http request: %v", err)
+			}
+
+			request = request.WithContext(context.WithValue(request.Context(), akumu.ProblemsKey{}, controls))
+			request.Header.Set("Accept", "application/problem+json")
+
+			if test.language != "" {
+				request.Header.Set("Accept-Language", test.language)
+			}
+
+			response := akumu.Record(func(*http.Request) error { return test.problem }, request)
+
+			var problem akumu.Problem
+
+			if err := json.Unmarshal(response.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("failed to decode problem: %v", err)
+			}
+
+			if problem.Title != test.title {
+				t.Fatalf("expected title %q but got %q", test.title, problem.Title)
+			}
+
+			if problem.Detail != test.detail {
+				t.Fatalf("expected detail %q but got %q", test.detail, problem.Detail)
+			}
+
+			if language := response.Header().Get("Content-Language"); language != test.content {
+				t.Fatalf("expected content language %q but got %q", test.content, language)
+			}
+
+			if expected := "Accept-Language"; response.Header().Get("Vary") != expected {
+				t.Fatalf("expected vary %q but got %q", expected, response.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestProblemCatalogTags(t *testing.T) {
+	catalog := akumu.ProblemCatalog{
+		Fallback: "fr",
+		Languages: map[string]akumu.ProblemTranslations{
+			"es": {}, "ca": {}, "fr": {},
+		},
+	}
+
+	tags := catalog.Tags()
+
+	if len(tags) != 3 || tags[0] != "fr" || tags[1] != "ca" || tags[2] != "es" {
+		t.Fatalf("expected tags [fr ca es] but got %v", tags)
+	}
+}
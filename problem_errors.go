@@ -0,0 +1,102 @@
+package akumu
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ProblemError is a node of the errors tree of a [Problem],
+// as returned by [Problem.Errors] and serialized in the errors
+// extension member.
+type ProblemError struct {
+
+	// Message is the message of the error.
+	Message string `json:"message"`
+
+	// Type is the Go type of the error.
+	Type string `json:"type"`
+
+	// Depth is the wrap depth of the error, starting at
+	// zero for the errors bound to the [Problem].
+	Depth int `json:"depth"`
+
+	// Stack stores the stack frames of the error, if
+	// it implements the [StackTracer] interface.
+	Stack []StackFrame `json:"stack,omitempty"`
+
+	// Causes stores the errors wrapped by this error.
+	Causes []ProblemError `json:"causes,omitempty"`
+
+	// err is the original error of the node.
+	err error
+}
+
+// errorTree creates the errors tree of the given error at the given
+// depth. Errors joined using [errors.Join] are flattened into siblings
+// while errors wrapped using [fmt.Errorf] with the `%w` directive
+// become the causes of the error wrapping them.
+func errorTree(err error, depth int) []ProblemError {
+	trace := stackTrace(err)
+	tree := make([]ProblemError, len(trace))
+
+	for i, current := range trace {
+		tree[i] = ProblemError{
+			Message: current.Error(),
+			Type:    fmt.Sprintf("%T", current),
+			Depth:   depth,
+			err:     current,
+		}
+
+		if tracer, ok := current.(StackTracer); ok {
+			tree[i].Stack = tracer.StackFrames()
+		}
+
+		if wrapped := errors.Unwrap(current); wrapped != nil {
+			tree[i].Causes = errorTree(wrapped, depth+1)
+		}
+	}
+
+	return tree
+}
+
+// Err returns the original error of the node.
+func (node ProblemError) Err() error {
+	return node.err
+}
+
+// lowercased returns the node with its message,
+// and the messages of its causes, in lowercase.
+func (node ProblemError) lowercased() ProblemError {
+	node.Message = strings.ToLower(node.Message)
+	causes := make([]ProblemError, len(node.Causes))
+
+	for i, cause := range node.Causes {
+		causes[i] = cause.lowercased()
+	}
+
+	if len(causes) > 0 {
+		node.Causes = causes
+	}
+
+	return node
+}
+
+// errorLines returns the messages and stack frames of the given
+// errors tree, one per line, indented by their wrap depth.
+func errorLines(tree []ProblemError) []string {
+	lines := make([]string, 0, len(tree))
+
+	for _, node := range tree {
+		indent := strings.Repeat("  ", node.Depth)
+		lines = append(lines, indent+node.Message)
+
+		for _, frame := range node.Stack {
+			lines = append(lines, fmt.Sprintf("%s    at %s (%s:%d)", indent, frame.Function, frame.File, frame.Line))
+		}
+
+		lines = append(lines, errorLines(node.Causes)...)
+	}
+
+	return lines
+}
@@ -0,0 +1,162 @@
+package akumu
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+)
+
+// ProblemPage is the data given to the templates that
+// render the HTML representation of a [Problem].
+type ProblemPage struct {
+
+	// Problem is the defaulted [Problem] to render.
+	Problem Problem
+
+	// Members stores the extension members of the problem,
+	// sorted by name, excluding the errors trace.
+	Members []ProblemMember
+
+	// Errors stores the errors tree of the problem. It's
+	// empty when [ProblemControls]'s Errors returns false.
+	Errors []ProblemError
+}
+
+// ProblemMember is an extension member of a [Problem],
+// as rendered by the HTML representation.
+type ProblemMember struct {
+
+	// Name is the name of the extension member.
+	Name string
+
+	// Value is the value of the extension member. Values
+	// other than strings are rendered using their JSON
+	// representation.
+	Value string
+}
+
+// ProblemTemplates stores the templates used to render
+// the HTML representation of a [Problem], keyed by problem
+// type and status.
+type ProblemTemplates struct {
+
+	// Types stores the templates keyed by the problem type.
+	// They take precedence over the templates of Statuses.
+	Types map[string]*template.Template
+
+	// Statuses stores the templates keyed by the problem status.
+	Statuses map[int]*template.Template
+
+	// Default is the template used when there's no template
+	// for the problem type nor status. The built-in template
+	// is used if it's nil.
+	Default *template.Template
+}
+
+// problemTemplateSource is the source of the built-in
+// template of the HTML representation of a [Problem].
+//
+//go:embed problem.html
+var problemTemplateSource string
+
+// problemTemplate is the built-in template of the
+// HTML representation of a [Problem].
+var problemTemplate = template.Must(template.New("problem").Parse(problemTemplateSource))
+
+// ProblemControlsTemplate is a helper that generates a [ProblemControlsResolver]
+// that resolves to the template of the given [ProblemTemplates] that matches
+// the problem type or status.
+func ProblemControlsTemplate(templates ProblemTemplates) ProblemControlsResolver[*template.Template] {
+	return func(problem Problem, request *http.Request) *template.Template {
+		if tmpl, ok := templates.Types[problem.Type]; ok && problem.Type != "" {
+			return tmpl
+		}
+
+		if tmpl, ok := templates.Statuses[problem.Status]; ok {
+			return tmpl
+		}
+
+		if templates.Default != nil {
+			return templates.Default
+		}
+
+		return problemTemplate
+	}
+}
+
+// defaultProblemControlsTemplate is the default value for the [ProblemControls] template.
+func defaultProblemControlsTemplate(problem Problem, request *http.Request) *template.Template {
+	return problemTemplate
+}
+
+// page returns the [ProblemPage] of the given defaulted problem.
+func (problem Problem) page(request *http.Request) ProblemPage {
+	controls := problem.controls(request)
+	key := controls.ErrorsKey(problem, request)
+	page := ProblemPage{
+		Problem: problem,
+		Members: make([]ProblemMember, 0, len(problem.additional)),
+	}
+
+	if traces, ok := problem.additional[key].([]ProblemError); ok && controls.Errors(problem, request) {
+		page.Errors = traces
+	}
+
+	for name, value := range problem.additional {
+		if name == key {
+			continue
+		}
+
+		text, ok := value.(string)
+
+		if !ok {
+			encoded, err := json.Marshal(value)
+
+			if err != nil {
+				encoded = []byte(fmt.Sprint(value))
+			}
+
+			text = string(encoded)
+		}
+
+		page.Members = append(page.Members, ProblemMember{Name: name, Value: text})
+	}
+
+	slices.SortFunc(page.Members, func(a, b ProblemMember) int {
+		if a.Name < b.Name {
+			return -1
+		}
+
+		if a.Name > b.Name {
+			return 1
+		}
+
+		return 0
+	})
+
+	return page
+}
+
+// problemHTMLResponse returns the "text/html" response of the given
+// problem. The template is only rendered once the response is written,
+// falling back to a plain text body if the rendering fails.
+func problemHTMLResponse(problem Problem, request *http.Request) Builder {
+	return Response(problem.Status).
+		Header("Content-Type", "text/html; charset=utf-8").
+		BodyWriter(func(writer http.ResponseWriter) {
+			tmpl := problem.controls(request).Template(problem, request)
+			buffer := &bytes.Buffer{}
+
+			if err := tmpl.Execute(buffer, problem.page(request)); err != nil {
+				_, _ = fmt.Fprintf(writer, "%d %s", problem.Status, template.HTMLEscapeString(problem.Title))
+
+				return
+			}
+
+			_, _ = buffer.WriteTo(writer)
+		})
+}
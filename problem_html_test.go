@@ -0,0 +1,129 @@
+package akumu_test
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+)
+
+func problemHTMLTestRequest(t *testing.T, controls akumu.ProblemControls) *http.Request {
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+
+	return request.WithContext(context.WithValue(request.Context(), akumu.ProblemsKey{}, controls))
+}
+
+func TestProblemHTMLResponse(t *testing.T) {
+	handler := func(*http.Request) error {
+		return akumu.Problem{
+			Title:  "Bad <Request>",
+			Detail: `<script>alert("detail")</script>`,
+			Status: http.StatusBadRequest,
+		}.
+			WithError(errors.New("<b>secret</b>")).
+			With("field", "<img src=x>")
+	}
+
+	request := problemHTMLTestRequest(t, akumu.ProblemControls{})
+	response := akumu.Record(handler, request)
+	body := response.Body.String()
+
+	if response.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d but got %d", http.StatusBadRequest, response.Code)
+	}
+
+	if expected := "text/html; charset=utf-8"; response.Header().Get("Content-Type") != expected {
+		t.Fatalf("expected content type %s but got %s", expected, response.Header().Get("Content-Type"))
+	}
+
+	for _, unsafe := range []string{"<script>", "<img", "<b>", "<request>"} {
+		if strings.Contains(body, unsafe) {
+			t.Fatalf("expected body to escape %s but got %s", unsafe, body)
+		}
+	}
+
+	for _, expected := range []string{"&lt;script&gt;", "&lt;img src=x&gt;", "&lt;b&gt;secret&lt;/b&gt;"} {
+		if !strings.Contains(body, expected) {
+			t.Fatalf("expected body to contain %s but got %s", expected, body)
+		}
+	}
+}
+
+func TestProblemHTMLResponseFailed(t *testing.T) {
+	handler := func(*http.Request) error {
+		return akumu.Failed(errors.New("failed to load"))
+	}
+
+	request := problemHTMLTestRequest(t, akumu.ProblemControls{})
+	response := akumu.Record(handler, request)
+
+	if body := response.Body.String(); !strings.Contains(body, "failed to load") {
+		t.Fatalf("expected body to contain the error but got %s", body)
+	}
+}
+
+func TestProblemHTMLResponseWithoutErrors(t *testing.T) {
+	handler := func(*http.Request) error {
+		return akumu.NewProblem(errors.New("secret failure"), http.StatusInternalServerError).
+			With("detail-key", "visible")
+	}
+
+	request := problemHTMLTestRequest(t, akumu.ProblemControls{
+		DefaultDetails: func(akumu.Problem, *http.Request) string { return "Something went wrong." },
+		Errors:         func(akumu.Problem, *http.Request) bool { return false },
+	})
+
+	body := akumu.Record(handler, request).Body.String()
+
+	if strings.Contains(body, "secret failure") {
+		t.Fatalf("expected body to hide the errors trace but got %s", body)
+	}
+
+	if !strings.Contains(body, "visible") {
+		t.Fatalf("expected body to contain the extension members but got %s", body)
+	}
+}
+
+func TestProblemHTMLTemplates(t *testing.T) {
+	templates := akumu.ProblemTemplates{
+		Types: map[string]*template.Template{
+			ErrNotAuthenticated.Type: template.Must(template.New("type").Parse(`type {{ .Problem.Title }}`)),
+		},
+		Statuses: map[int]*template.Template{
+			http.StatusNotFound: template.Must(template.New("status").Parse(`status {{ .Problem.Status }}`)),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		problem  akumu.Problem
+		expected string
+	}{
+		{"type", ErrNotAuthenticated, "type unauthenticated"},
+		{"status", akumu.NewProblem(nil, http.StatusNotFound), "status 404"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := problemHTMLTestRequest(t, akumu.ProblemControls{
+				Template: akumu.ProblemControlsTemplate(templates),
+			})
+
+			response := akumu.Record(func(*http.Request) error { return test.problem }, request)
+
+			if body := response.Body.String(); body != test.expected {
+				t.Fatalf("expected body %q but got %q", test.expected, body)
+			}
+		})
+	}
+}
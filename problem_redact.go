@@ -0,0 +1,75 @@
+package akumu
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+)
+
+// defaultProblemControlsRedact is the default value for the [ProblemControls] redact.
+func defaultProblemControlsRedact(problem Problem, request *http.Request) bool {
+	return false
+}
+
+// defaultProblemControlsRedactedDetails is the default value for the [ProblemControls] redacted details.
+func defaultProblemControlsRedactedDetails(problem Problem, request *http.Request) string {
+	return "The server encountered an internal error. Please refer to the occurrence when reporting it."
+}
+
+// defaultProblemControlsOccurrence is the default value for the [ProblemControls] occurrence.
+// It's a random 128 bits identifier, encoded as hexadecimal.
+func defaultProblemControlsOccurrence(problem Problem, request *http.Request) string {
+	occurrence := make([]byte, 16)
+	_, _ = rand.Read(occurrence)
+
+	return hex.EncodeToString(occurrence)
+}
+
+// redacts reports whether the problem is a server
+// error that must be redacted.
+func (problem Problem) redacts(request *http.Request, controls ProblemControls) bool {
+	return problem.Status >= 500 &&
+		problem.Status < 600 &&
+		controls.Redact(problem, request)
+}
+
+// redacted returns the problem without its details nor its errors trace,
+// including an opaque occurrence identifier instead, along with the
+// [ErrRedacted] that keeps the full error chain, keyed by that identifier.
+func (problem Problem) redacted(request *http.Request, controls ProblemControls) (Problem, ErrRedacted) {
+	occurrence := controls.Occurrence(problem, request)
+
+	err := ErrRedacted{
+		Occurrence: occurrence,
+		Problem:    problem,
+		Request:    request,
+	}
+
+	problem.Detail = controls.RedactedDetails(problem, request)
+
+	problem = problem.
+		Without(controls.ErrorsKey(problem, request)).
+		With("occurrence", occurrence)
+
+	return problem, err
+}
+
+// report gives the redacted error to the [OnErrorHook] subscribers
+// of the request, or logs it using [log/slog.Default] if there's none.
+func (err ErrRedacted) report(request *http.Request) {
+	if hooks := RequestHooks(request); len(hooks.OnError) > 0 {
+		hooks.RunError(err)
+
+		return
+	}
+
+	slog.Default().ErrorContext(
+		request.Context(),
+		"redacted problem",
+		"occurrence", err.Occurrence,
+		"status", err.Problem.Status,
+		"url", request.URL,
+		"error", err.Problem.err,
+	)
+}
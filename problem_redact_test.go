@@ -0,0 +1,155 @@
+package akumu_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+)
+
+func TestProblemRedaction(t *testing.T) {
+	databaseErr := errors.New("pq: password authentication failed for user \"admin\"")
+	reported := make([]error, 0)
+
+	controls := akumu.ProblemControls{
+		Redact:     func(akumu.Problem, *http.Request) bool { return true },
+		Occurrence: func(akumu.Problem, *http.Request) string { return "abc123" },
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	ctx := context.WithValue(request.Context(), akumu.ProblemsKey{}, controls)
+	ctx = context.WithValue(ctx, akumu.HooksKey{}, akumu.Hooks{
+		OnError: []akumu.OnErrorHook{func(err error) {
+			reported = append(reported, err)
+		}},
+	})
+
+	request = request.WithContext(ctx)
+	request.Header.Set("Accept", "application/problem+json")
+
+	response := akumu.Record(func(*http.Request) error { return databaseErr }, request)
+
+	if response.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d but got %d", http.StatusInternalServerError, response.Code)
+	}
+
+	if strings.Contains(response.Body.String(), "password") {
+		t.Fatalf("expected body to be redacted but got %s", response.Body.String())
+	}
+
+	data := make(map[string]any)
+
+	if err := json.Unmarshal(response.Body.Bytes(), &data); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+
+	if data["occurrence"] != "abc123" {
+		t.Fatalf("expected occurrence abc123 but got %v", data["occurrence"])
+	}
+
+	if _, ok := data["errors"]; ok {
+		t.Fatalf("expected errors trace to be hidden but got %v", data["errors"])
+	}
+
+	redacted := akumu.ErrRedacted{}
+
+	if len(reported) != 1 || !errors.As(reported[0], &redacted) || redacted.Occurrence != "abc123" {
+		t.Fatalf("expected one redacted error with the occurrence but got %v", reported)
+	}
+
+	if !errors.Is(redacted, databaseErr) {
+		t.Fatalf("expected the redacted error to keep the full error chain")
+	}
+}
+
+func TestProblemRedactionClientErrors(t *testing.T) {
+	controls := akumu.ProblemControls{
+		Redact: func(akumu.Problem, *http.Request) bool { return true },
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request = request.WithContext(context.WithValue(request.Context(), akumu.ProblemsKey{}, controls))
+	problem := akumu.NewProblem(errors.New("name is required"), http.StatusBadRequest).Defaulted(request)
+
+	if expected := "name is required"; problem.Detail != expected {
+		t.Fatalf("expected detail %s but got %s", expected, problem.Detail)
+	}
+
+	if _, ok := problem.Additional("occurrence"); ok {
+		t.Fatalf("expected client errors to not be redacted")
+	}
+}
+
+func TestProblemRedactionText(t *testing.T) {
+	controls := akumu.ProblemControls{
+		Redact:     func(akumu.Problem, *http.Request) bool { return true },
+		Occurrence: func(akumu.Problem, *http.Request) string { return "abc123" },
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request = request.WithContext(context.WithValue(request.Context(), akumu.ProblemsKey{}, controls))
+	response := akumu.Record(func(*http.Request) error { return errors.New("secret") }, request)
+
+	if expected := "text/plain"; response.Header().Get("Content-Type") != expected {
+		t.Fatalf("expected content type %s but got %s", expected, response.Header().Get("Content-Type"))
+	}
+
+	if !strings.Contains(response.Body.String(), "abc123") {
+		t.Fatalf("expected body to contain the occurrence but got %s", response.Body.String())
+	}
+
+	if strings.Contains(response.Body.String(), "secret") {
+		t.Fatalf("expected body to be redacted but got %s", response.Body.String())
+	}
+}
+
+func TestProblemRedactionDefaulted(t *testing.T) {
+	reported := 0
+
+	controls := akumu.ProblemControls{
+		Redact: func(akumu.Problem, *http.Request) bool { return true },
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	ctx := context.WithValue(request.Context(), akumu.ProblemsKey{}, controls)
+	ctx = context.WithValue(ctx, akumu.HooksKey{}, akumu.Hooks{
+		OnError: []akumu.OnErrorHook{func(err error) {
+			reported++
+		}},
+	})
+
+	request = request.WithContext(ctx)
+	problem := akumu.NewProblem(errors.New("secret"), http.StatusInternalServerError).Defaulted(request)
+
+	if _, ok := problem.Additional("occurrence"); !ok {
+		t.Fatalf("expected the problem to be redacted")
+	}
+
+	if reported != 0 {
+		t.Fatalf("expected no reported errors but got %d", reported)
+	}
+}
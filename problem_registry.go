@@ -0,0 +1,261 @@
+package akumu
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// ProblemDefinition defines the [Problem] that
+// an error is mapped to once registered in
+// a [ProblemRegistry].
+type ProblemDefinition struct {
+
+	// Type is the URI reference that
+	// identifies the problem type.
+	Type string
+
+	// Title is the short, human-readable
+	// summary of the problem type.
+	Title string
+
+	// Status is the http status code of the problem.
+	Status int
+
+	// Detail is the human-readable explanation of the problem.
+	// When empty, the [ProblemControls] DefaultDetails is used.
+	Detail string
+
+	// Extensions builds the extension members of the problem
+	// from the error that has been matched, if given.
+	Extensions func(err error) map[string]any
+
+	// Description is the human-readable documentation of the
+	// problem type, served by [ProblemTypesHandler].
+	Description string
+
+	// Example stores example extension members of the problem
+	// type, served by [ProblemTypesHandler].
+	Example map[string]any
+}
+
+// problemEntry is a [ProblemDefinition] along with the function
+// that matches the errors and the target it was registered with, if any.
+type problemEntry struct {
+	target     error
+	match      func(err error) bool
+	definition ProblemDefinition
+}
+
+// ProblemRegistry stores the registered [ProblemDefinition] in
+// registration order. The zero value is an empty registry ready to use.
+//
+// Use [ProblemControlsRegistry] to scope a registry to the requests
+// of a [ProblemControls], instead of using the [DefaultProblemRegistry].
+type ProblemRegistry struct {
+	mutex   sync.RWMutex
+	entries []problemEntry
+}
+
+// DefaultProblemRegistry is the [ProblemRegistry] used by [RegisterProblem],
+// [RegisterProblemAs], [LookupProblem] and [ProblemDefinitions], and the
+// default one of the [ProblemControls].
+var DefaultProblemRegistry = &ProblemRegistry{}
+
+// ProblemControlsRegistry is a helper that generates a
+// [ProblemControlsResolver] that always resolves to the
+// given [ProblemRegistry].
+func ProblemControlsRegistry(registry *ProblemRegistry) ProblemControlsResolver[*ProblemRegistry] {
+	return func(problem Problem, request *http.Request) *ProblemRegistry {
+		return registry
+	}
+}
+
+// defaultProblemControlsRegistry is the default value for the [ProblemControls] registry.
+func defaultProblemControlsRegistry(problem Problem, request *http.Request) *ProblemRegistry {
+	return DefaultProblemRegistry
+}
+
+// RegisterProblem maps the errors that match the given target
+// using [errors.Is] to the given [ProblemDefinition] in the
+// [DefaultProblemRegistry].
+//
+// Once registered, handlers may return the error, or any error
+// wrapping it, and it will be responded as the defined [Problem]
+// instead of a generic [http.StatusInternalServerError].
+func RegisterProblem(target error, definition ProblemDefinition) {
+	DefaultProblemRegistry.Register(target, definition)
+}
+
+// RegisterProblemAs maps the errors that match the type `E` using
+// [errors.As] to the given [ProblemDefinition] in the [DefaultProblemRegistry].
+//
+// See [RegisterProblem] for more details.
+func RegisterProblemAs[E error](definition ProblemDefinition) {
+	DefaultProblemRegistry.RegisterFunc(ProblemMatchAs[E], definition)
+}
+
+// ProblemMatchAs reports whether the given error matches the
+// type `E` using [errors.As]. It's meant to be used along with
+// [ProblemRegistry.RegisterFunc].
+func ProblemMatchAs[E error](err error) bool {
+	var target E
+
+	return errors.As(err, &target)
+}
+
+// LookupProblem returns the [Problem] that the given error is mapped
+// to in the [DefaultProblemRegistry].
+//
+// See [ProblemRegistry.Lookup] for more details.
+func LookupProblem(err error) (Problem, bool) {
+	return DefaultProblemRegistry.Lookup(err)
+}
+
+// ProblemDefinitions returns the [ProblemDefinition] of the
+// [DefaultProblemRegistry] that have a problem type.
+//
+// See [ProblemRegistry.Definitions] for more details.
+func ProblemDefinitions() []ProblemDefinition {
+	return DefaultProblemRegistry.Definitions()
+}
+
+// Register maps the errors that match the given target
+// using [errors.Is] to the given [ProblemDefinition].
+func (registry *ProblemRegistry) Register(target error, definition ProblemDefinition) {
+	registry.register(problemEntry{
+		target: target,
+		match: func(err error) bool {
+			return errors.Is(err, target)
+		},
+		definition: definition,
+	})
+}
+
+// RegisterFunc maps the errors that the given match
+// function reports to the given [ProblemDefinition].
+func (registry *ProblemRegistry) RegisterFunc(match func(err error) bool, definition ProblemDefinition) {
+	registry.register(problemEntry{
+		match:      match,
+		definition: definition,
+	})
+}
+
+// register appends the given entry to the registered ones.
+func (registry *ProblemRegistry) register(entry problemEntry) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.entries = append(registry.entries, entry)
+}
+
+// Unregister removes the [ProblemDefinition] registered with
+// the given target using [ProblemRegistry.Register]. Targets that
+// are not comparable, such as struct errors with slice fields,
+// are never removed, as they cannot be told apart.
+//
+// Returns true if any definition was removed.
+func (registry *ProblemRegistry) Unregister(target error) bool {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	length := len(registry.entries)
+
+	comparable := target != nil && reflect.TypeOf(target).Comparable()
+
+	registry.entries = slices.DeleteFunc(registry.entries, func(entry problemEntry) bool {
+		return comparable && entry.target == target
+	})
+
+	return len(registry.entries) != length
+}
+
+// Reset removes all the registered [ProblemDefinition].
+func (registry *ProblemRegistry) Reset() {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.entries = nil
+}
+
+// Lookup returns the [Problem] that the given error is mapped to,
+// using the first registered [ProblemDefinition] that matches it.
+// The returned [Problem] keeps the error.
+//
+// The second return value determines if the error was mapped or not.
+func (registry *ProblemRegistry) Lookup(err error) (Problem, bool) {
+	if err == nil {
+		return Problem{}, false
+	}
+
+	registry.mutex.RLock()
+
+	index := slices.IndexFunc(registry.entries, func(entry problemEntry) bool {
+		return entry.match(err)
+	})
+
+	if index < 0 {
+		registry.mutex.RUnlock()
+
+		return Problem{}, false
+	}
+
+	definition := registry.entries[index].definition
+
+	// The problem is built once unlocked, as the definition's
+	// extensions may use the registry as well.
+	registry.mutex.RUnlock()
+
+	return definition.Problem(err), true
+}
+
+// Definitions returns the registered [ProblemDefinition] that
+// have a problem type, in registration order. When more than one
+// definition has the same problem type, only the first one is returned.
+func (registry *ProblemRegistry) Definitions() []ProblemDefinition {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	definitions := make([]ProblemDefinition, 0, len(registry.entries))
+
+	for _, entry := range registry.entries {
+		kind := entry.definition.Type
+
+		if kind == "" || kind == "about:blank" {
+			continue
+		}
+
+		exists := slices.ContainsFunc(definitions, func(definition ProblemDefinition) bool {
+			return definition.Type == kind
+		})
+
+		if !exists {
+			definitions = append(definitions, entry.definition)
+		}
+	}
+
+	return definitions
+}
+
+// Problem creates the [Problem] of the definition
+// with the given error, that may be nil.
+func (definition ProblemDefinition) Problem(err error) Problem {
+	problem := Problem{
+		err:    err,
+		Type:   definition.Type,
+		Title:  definition.Title,
+		Status: definition.Status,
+		Detail: definition.Detail,
+	}
+
+	if definition.Extensions != nil {
+		if extensions := definition.Extensions(err); len(extensions) > 0 {
+			problem.additional = maps.Clone(extensions)
+		}
+	}
+
+	return problem
+}
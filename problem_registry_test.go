@@ -0,0 +1,255 @@
+package akumu_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+)
+
+var ErrRegistryTestNotFound = errors.New("registry test resource not found")
+
+type RegistryTestError struct {
+	Field string
+}
+
+func (err RegistryTestError) Error() string {
+	return fmt.Sprintf("invalid field %s", err.Field)
+}
+
+type RegistryTestFieldsError struct {
+	Fields []string
+}
+
+func (err RegistryTestFieldsError) Error() string {
+	return fmt.Sprintf("invalid fields %v", err.Fields)
+}
+
+func registryTestRegistry() *akumu.ProblemRegistry {
+	registry := &akumu.ProblemRegistry{}
+
+	registry.Register(ErrRegistryTestNotFound, akumu.ProblemDefinition{
+		Type:   "https://example.com/problems/not-found",
+		Title:  "Not Found",
+		Status: http.StatusNotFound,
+	})
+
+	registry.RegisterFunc(akumu.ProblemMatchAs[RegistryTestError], akumu.ProblemDefinition{
+		Type:   "https://example.com/problems/invalid-field",
+		Title:  "Invalid Field",
+		Status: http.StatusUnprocessableEntity,
+		Detail: "The field is invalid.",
+		Extensions: func(err error) map[string]any {
+			var target RegistryTestError
+
+			errors.As(err, &target)
+
+			return map[string]any{"field": target.Field}
+		},
+	})
+
+	return registry
+}
+
+func registryTestRequest(t *testing.T, registry *akumu.ProblemRegistry) *http.Request {
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	controls := akumu.ProblemControls{
+		Registry: akumu.ProblemControlsRegistry(registry),
+	}
+
+	request = request.WithContext(context.WithValue(request.Context(), akumu.ProblemsKey{}, controls))
+	request.Header.Set("Accept", "application/problem+json")
+
+	return request
+}
+
+func TestLookupProblem(t *testing.T) {
+	registry := registryTestRegistry()
+	problem, ok := registry.Lookup(fmt.Errorf("user 10: %w", ErrRegistryTestNotFound))
+
+	if !ok {
+		t.Fatalf("expected error to be mapped to a problem")
+	}
+
+	if expected := http.StatusNotFound; problem.Status != expected {
+		t.Fatalf("expected status %d but got %d", expected, problem.Status)
+	}
+
+	if !errors.Is(problem, ErrRegistryTestNotFound) {
+		t.Fatalf("expected problem to keep the error")
+	}
+
+	if _, ok := registry.Lookup(errors.New("unknown")); ok {
+		t.Fatalf("expected unknown error to not be mapped")
+	}
+}
+
+func TestRegisteredProblemResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler akumu.Handler
+		status  int
+		kind    string
+	}{
+		{"is", func(*http.Request) error { return fmt.Errorf("user 10: %w", ErrRegistryTestNotFound) }, http.StatusNotFound, "https://example.com/problems/not-found"},
+		{"as", func(*http.Request) error { return RegistryTestError{Field: "name"} }, http.StatusUnprocessableEntity, "https://example.com/problems/invalid-field"},
+		{"failed", func(*http.Request) error { return akumu.Failed(ErrRegistryTestNotFound) }, http.StatusNotFound, "https://example.com/problems/not-found"},
+		{"unknown", func(*http.Request) error { return errors.New("unknown") }, http.StatusInternalServerError, "about:blank"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := test.handler.Record(registryTestRequest(t, registryTestRegistry()))
+
+			if response.Code != test.status {
+				t.Fatalf("expected status %d but got %d", test.status, response.Code)
+			}
+
+			data := make(map[string]any)
+
+			if err := json.Unmarshal(response.Body.Bytes(), &data); err != nil {
+				t.Fatalf("failed to decode problem: %v", err)
+			}
+
+			if data["type"] != test.kind {
+				t.Fatalf("expected type %s but got %v", test.kind, data["type"])
+			}
+
+			if test.name == "as" && data["field"] != "name" {
+				t.Fatalf("expected field name but got %v", data["field"])
+			}
+		})
+	}
+}
+
+func TestProblemRegistry(t *testing.T) {
+	registry := &akumu.ProblemRegistry{}
+
+	registry.Register(ErrRegistryTestNotFound, akumu.ProblemDefinition{
+		Type:   "https://example.com/problems/gone",
+		Status: http.StatusGone,
+	})
+
+	registry.RegisterFunc(akumu.ProblemMatchAs[RegistryTestError], akumu.ProblemDefinition{
+		Status: http.StatusBadRequest,
+	})
+
+	if problem, ok := registry.Lookup(ErrRegistryTestNotFound); !ok || problem.Status != http.StatusGone {
+		t.Fatalf("expected status %d but got %d", http.StatusGone, problem.Status)
+	}
+
+	if problem, ok := registry.Lookup(RegistryTestError{Field: "name"}); !ok || problem.Status != http.StatusBadRequest {
+		t.Fatalf("expected status %d but got %d", http.StatusBadRequest, problem.Status)
+	}
+
+	if definitions := registry.Definitions(); len(definitions) != 1 {
+		t.Fatalf("expected 1 definition but got %d", len(definitions))
+	}
+
+	if !registry.Unregister(ErrRegistryTestNotFound) {
+		t.Fatalf("expected the definition to be unregistered")
+	}
+
+	if _, ok := registry.Lookup(ErrRegistryTestNotFound); ok {
+		t.Fatalf("expected the error to not be mapped once unregistered")
+	}
+
+	if registry.Unregister(ErrRegistryTestNotFound) {
+		t.Fatalf("expected no definition to be unregistered")
+	}
+
+	registry.Reset()
+
+	if _, ok := registry.Lookup(RegistryTestError{Field: "name"}); ok {
+		t.Fatalf("expected the error to not be mapped once reset")
+	}
+
+	target := RegistryTestFieldsError{Fields: []string{"name"}}
+	registry.Register(target, akumu.ProblemDefinition{Status: http.StatusBadRequest})
+
+	if registry.Unregister(target) {
+		t.Fatalf("expected non-comparable targets to not be unregistered")
+	}
+}
+
+func TestProblemRegistryLookupRegisters(t *testing.T) {
+	registry := &akumu.ProblemRegistry{}
+
+	registry.Register(ErrRegistryTestNotFound, akumu.ProblemDefinition{
+		Status: http.StatusNotFound,
+		Extensions: func(err error) map[string]any {
+			registry.Register(ErrRegistryTestNotFound, akumu.ProblemDefinition{})
+
+			return nil
+		},
+	})
+
+	if _, ok := registry.Lookup(ErrRegistryTestNotFound); !ok {
+		t.Fatalf("expected error to be mapped to a problem")
+	}
+}
+
+func TestDefaultProblemRegistry(t *testing.T) {
+	target := errors.New("default registry test")
+
+	akumu.RegisterProblem(target, akumu.ProblemDefinition{Status: http.StatusTeapot})
+
+	t.Cleanup(func() {
+		akumu.DefaultProblemRegistry.Unregister(target)
+	})
+
+	if problem, ok := akumu.LookupProblem(target); !ok || problem.Status != http.StatusTeapot {
+		t.Fatalf("expected status %d but got %d", http.StatusTeapot, problem.Status)
+	}
+
+	if _, ok := registryTestRegistry().Lookup(target); ok {
+		t.Fatalf("expected other registries to be untouched")
+	}
+}
+
+func TestProblemControlsRegistry(t *testing.T) {
+	registry := &akumu.ProblemRegistry{}
+
+	registry.Register(ErrRegistryTestNotFound, akumu.ProblemDefinition{
+		Type:   "https://example.com/problems/gone",
+		Status: http.StatusGone,
+	})
+
+	controls := akumu.ProblemControls{
+		Registry: akumu.ProblemControlsRegistry(registry),
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request = request.WithContext(context.WithValue(request.Context(), akumu.ProblemsKey{}, controls))
+	request.Header.Set("Accept", "application/problem+json")
+
+	handler := akumu.Handler(func(*http.Request) error {
+		return ErrRegistryTestNotFound
+	})
+
+	if response := handler.Record(request); response.Code != http.StatusGone {
+		t.Fatalf("expected status %d but got %d", http.StatusGone, response.Code)
+	}
+
+	handler = func(*http.Request) error {
+		return RegistryTestError{Field: "name"}
+	}
+
+	if response := handler.Record(request); response.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d but got %d", http.StatusInternalServerError, response.Code)
+	}
+}
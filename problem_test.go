@@ -208,3 +208,45 @@ func TestProblemDirectError(t *testing.T) {
 		t.Fatalf("expected %d errors but got %d", expected, len(errs))
 	}
 }
+
+func TestProblemErrorsHierarchy(t *testing.T) {
+	request, err := http.NewRequest("GET", "/", nil)
+
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+
+	request.Header.Add("Accept", "application/problem+json")
+
+	response := akumu.Record(customProblemHandlerDirectErr, request)
+
+	data := struct {
+		Errors []akumu.ProblemError `json:"errors"`
+	}{}
+
+	if err := json.Unmarshal(response.Body.Bytes(), &data); err != nil {
+		t.Fatalf("unable to deserialize response body: %s", err)
+	}
+
+	if expected := 4; len(data.Errors) != expected {
+		t.Fatalf("expected %d errors but got %d", expected, len(data.Errors))
+	}
+
+	last := data.Errors[3]
+
+	if expected := "last error: failed"; last.Message != expected {
+		t.Fatalf("expected message '%s' but got '%s'", expected, last.Message)
+	}
+
+	if expected := 1; len(last.Causes) != expected {
+		t.Fatalf("expected %d causes but got %d", expected, len(last.Causes))
+	}
+
+	if expected := "last error"; last.Causes[0].Message != expected {
+		t.Fatalf("expected message '%s' but got '%s'", expected, last.Causes[0].Message)
+	}
+
+	if expected := 1; last.Causes[0].Depth != expected {
+		t.Fatalf("expected depth %d but got %d", expected, last.Causes[0].Depth)
+	}
+}
@@ -0,0 +1,180 @@
+package akumu
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/studiolambda/akumu/utils"
+)
+
+// ProblemDocument is the documentation of a problem type,
+// as served by [ProblemTypesHandler].
+type ProblemDocument struct {
+
+	// Type is the URI reference that
+	// identifies the problem type.
+	Type string `json:"type"`
+
+	// Title is the short, human-readable
+	// summary of the problem type.
+	Title string `json:"title"`
+
+	// Status is the http status code of the problem type.
+	Status int `json:"status"`
+
+	// Description is the human-readable
+	// documentation of the problem type.
+	Description string `json:"description,omitempty"`
+
+	// Example is an example [Problem] of the problem
+	// type, including its example extension members.
+	Example Problem `json:"example"`
+}
+
+var (
+	// ErrProblemTypeNotFound determines that there's no
+	// registered problem type with the requested URI.
+	ErrProblemTypeNotFound = errors.New("problem type not found")
+)
+
+// problemTypesSource is the source of the templates
+// used to render the problem types documentation.
+//
+//go:embed problem_types.html
+var problemTypesSource string
+
+// problemTypesTemplate stores the templates used to
+// render the problem types documentation.
+var problemTypesTemplate = template.Must(template.New("problem-types").Parse(problemTypesSource))
+
+// Document returns the [ProblemDocument] of the definition.
+func (definition ProblemDefinition) Document() ProblemDocument {
+	example := Problem{
+		Type:   definition.Type,
+		Title:  definition.Title,
+		Status: definition.Status,
+		Detail: definition.Detail,
+	}
+
+	for key, value := range definition.Example {
+		example = example.With(key, value)
+	}
+
+	return ProblemDocument{
+		Type:        definition.Type,
+		Title:       definition.Title,
+		Status:      definition.Status,
+		Description: definition.Description,
+		Example:     example,
+	}
+}
+
+// JSON returns the indented JSON representation of the
+// example [Problem], as rendered by the HTML documentation.
+func (document ProblemDocument) JSON() string {
+	encoded, err := json.MarshalIndent(document.Example, "", "  ")
+
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(encoded)
+}
+
+// problemTypePath returns the path of the given problem type URI.
+func problemTypePath(kind string) string {
+	parsed, err := url.Parse(kind)
+
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Path
+}
+
+// ProblemTypesHandler creates a [Handler] that serves the documentation
+// of the problem types registered in the [ProblemRegistry] of the request's
+// [ProblemControls], making their type URIs dereferenceable, as recommended by RFC 9457.
+//
+// A problem type is served when the request path matches the path of its
+// type URI, so the handler must be mounted where the type URIs point to.
+// Requests without a "type" path value are served a listing of all the
+// problem types instead. Use [Router.ProblemTypes] to mount it.
+//
+// The documentation is served as HTML or JSON, based on the request's
+// Accept header, defaulting to HTML.
+func ProblemTypesHandler() Handler {
+	return func(request *http.Request) error {
+		definitions := Problem{}.
+			controls(request).
+			Registry(Problem{}, request).
+			Definitions()
+		accept := utils.ParseAccept(request)
+		media := accept.Best("text/html", "application/json")
+
+		if request.PathValue("type") == "" {
+			documents := make([]ProblemDocument, len(definitions))
+
+			for i, definition := range definitions {
+				documents[i] = definition.Document()
+			}
+
+			return problemTypesResponse(media, "index", documents)
+		}
+
+		for _, definition := range definitions {
+			if problemTypePath(definition.Type) == request.URL.Path {
+				return problemTypesResponse(media, "type", definition.Document())
+			}
+		}
+
+		return NewProblem(
+			fmt.Errorf("%w: %s", ErrProblemTypeNotFound, request.URL.Path),
+			http.StatusNotFound,
+		)
+	}
+}
+
+// problemTypesResponse responds the given documentation using
+// the given media type and template, if it's HTML.
+func problemTypesResponse(media string, name string, data any) error {
+	response := Response(http.StatusOK).
+		AppendHeader("Vary", "Accept")
+
+	switch media {
+	case "application/json":
+		return response.JSON(data)
+	case "text/html":
+		buffer := &strings.Builder{}
+
+		if err := problemTypesTemplate.ExecuteTemplate(buffer, name, data); err != nil {
+			return NewProblem(err, http.StatusInternalServerError)
+		}
+
+		return response.HTML(buffer.String())
+	}
+
+	return NewProblem(ErrNotAcceptable, http.StatusNotAcceptable)
+}
+
+// ProblemTypes registers the [ProblemTypesHandler] in the router, serving
+// the listing of problem types in the given pattern and the documentation
+// of each problem type under it.
+//
+// For example, using "/problems" serves the listing in "/problems" and
+// the problem type "https://example.com/problems/out-of-stock" in
+// "/problems/out-of-stock".
+func (router *Router) ProblemTypes(pattern string) Routes {
+	handler := ProblemTypesHandler()
+
+	return Routes{
+		router.Get(pattern, handler),
+		router.Get(strings.TrimSuffix(pattern, "/")+"/{type...}", handler),
+	}
+}
@@ -0,0 +1,113 @@
+package akumu_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+)
+
+var ErrTypesTestOutOfStock = errors.New("types test out of stock")
+
+func problemTypesTestRequest(t *testing.T, path string, accept string) *http.Request {
+	request, err := http.NewRequest(http.MethodGet, path, nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	registry := &akumu.ProblemRegistry{}
+
+	registry.Register(ErrTypesTestOutOfStock, akumu.ProblemDefinition{
+		Type:        "https://example.com/problems/out-of-stock",
+		Title:       "Out of <stock>",
+		Status:      http.StatusConflict,
+		Description: "The product is no longer available.",
+		Example:     map[string]any{"product": 10},
+	})
+
+	controls := akumu.ProblemControls{
+		Registry: akumu.ProblemControlsRegistry(registry),
+	}
+
+	request = request.WithContext(context.WithValue(request.Context(), akumu.ProblemsKey{}, controls))
+
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+
+	return request
+}
+
+func TestProblemTypesListing(t *testing.T) {
+	router := akumu.NewRouter()
+	router.ProblemTypes("/problems")
+
+	response := router.Record(problemTypesTestRequest(t, "/problems", "application/json"))
+
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected status %d but got %d", http.StatusOK, response.Code)
+	}
+
+	documents := make([]map[string]any, 0)
+
+	if err := json.Unmarshal(response.Body.Bytes(), &documents); err != nil {
+		t.Fatalf("failed to decode documents: %v", err)
+	}
+
+	if len(documents) != 1 || documents[0]["type"] != "https://example.com/problems/out-of-stock" {
+		t.Fatalf("expected listing to only contain the registered type but got %s", response.Body.String())
+	}
+
+	response = router.Record(problemTypesTestRequest(t, "/problems", ""))
+
+	if body := response.Body.String(); !strings.Contains(body, `<a href="https://example.com/problems/out-of-stock">Out of &lt;stock&gt;</a>`) {
+		t.Fatalf("expected listing to link the registered type but got %s", body)
+	}
+}
+
+func TestProblemTypesPage(t *testing.T) {
+	router := akumu.NewRouter()
+	router.ProblemTypes("/problems")
+
+	response := router.Record(problemTypesTestRequest(t, "/problems/out-of-stock", "application/json"))
+
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected status %d but got %d", http.StatusOK, response.Code)
+	}
+
+	var document struct {
+		Type        string         `json:"type"`
+		Status      int            `json:"status"`
+		Description string         `json:"description"`
+		Example     map[string]any `json:"example"`
+	}
+
+	if err := json.Unmarshal(response.Body.Bytes(), &document); err != nil {
+		t.Fatalf("failed to decode document: %v", err)
+	}
+
+	if document.Status != http.StatusConflict || document.Description != "The product is no longer available." {
+		t.Fatalf("unexpected document: %+v", document)
+	}
+
+	if document.Example["product"] != float64(10) {
+		t.Fatalf("expected example product 10 but got %v", document.Example["product"])
+	}
+
+	response = router.Record(problemTypesTestRequest(t, "/problems/out-of-stock", "text/html"))
+
+	if body := response.Body.String(); !strings.Contains(body, "The product is no longer available.") {
+		t.Fatalf("expected page to contain the description but got %s", body)
+	}
+
+	response = router.Record(problemTypesTestRequest(t, "/problems/unknown", "application/json"))
+
+	if response.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d but got %d", http.StatusNotFound, response.Code)
+	}
+}
@@ -0,0 +1,285 @@
+package akumu
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ProblemNamespace is the XML namespace of the
+// "application/problem+xml" format, as defined in RFC 9457.
+const ProblemNamespace = "urn:ietf:rfc:7807"
+
+// MarshalXML replaces the default XML encoding behaviour, using
+// the "application/problem+xml" format defined in RFC 9457.
+//
+// Extension members are encoded from their JSON representation.
+// Arrays are encoded using an "i" element for each item and objects
+// using an element for each member. Members whose name is not a valid
+// XML NCName, such as names with spaces or colons, names starting with
+// a digit or names starting with "xml", are skipped.
+func (problem Problem) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{
+		Name: xml.Name{Space: ProblemNamespace, Local: "problem"},
+	}
+
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	members := []struct {
+		name  string
+		value any
+	}{
+		{"type", problem.Type},
+		{"title", problem.Title},
+		{"status", problem.Status},
+		{"detail", problem.Detail},
+		{"instance", problem.Instance},
+	}
+
+	for _, member := range members {
+		if err := encoder.EncodeElement(member.value, xmlElement(member.name)); err != nil {
+			return err
+		}
+	}
+
+	keys := make([]string, 0, len(problem.additional))
+
+	for key := range problem.additional {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if !xmlName(key) {
+			continue
+		}
+
+		value, err := codecValue(problem.additional[key])
+
+		if err != nil {
+			return err
+		}
+
+		if err := xmlEncode(encoder, key, value); err != nil {
+			return err
+		}
+	}
+
+	if err := encoder.EncodeToken(start.End()); err != nil {
+		return err
+	}
+
+	return encoder.Flush()
+}
+
+// UnmarshalXML replaces the default XML decoding behaviour, using
+// the "application/problem+xml" format defined in RFC 9457.
+//
+// Extension members are decoded as strings, []any for elements
+// made of "i" elements and map[string]any for any other element
+// with child elements.
+func (problem *Problem) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	value, err := xmlDecode(decoder)
+
+	if err != nil {
+		return err
+	}
+
+	mapped, ok := value.(map[string]any)
+
+	if !ok {
+		mapped = make(map[string]any)
+	}
+
+	if value, ok := mapped["type"].(string); ok {
+		problem.Type = value
+	}
+
+	if value, ok := mapped["title"].(string); ok {
+		problem.Title = value
+	}
+
+	if value, ok := mapped["status"].(string); ok {
+		status, err := strconv.Atoi(strings.TrimSpace(value))
+
+		if err != nil {
+			return err
+		}
+
+		problem.Status = status
+	}
+
+	if value, ok := mapped["detail"].(string); ok {
+		problem.Detail = value
+	}
+
+	if value, ok := mapped["instance"].(string); ok {
+		problem.Instance = value
+	}
+
+	delete(mapped, "type")
+	delete(mapped, "title")
+	delete(mapped, "status")
+	delete(mapped, "detail")
+	delete(mapped, "instance")
+
+	problem.additional = mapped
+
+	return nil
+}
+
+// xmlElement returns a start element with the given local name.
+func xmlElement(name string) xml.StartElement {
+	return xml.StartElement{Name: xml.Name{Local: name}}
+}
+
+// xmlName reports whether the given name is a valid XML NCName
+// that's not reserved, so that it can be used as an element name.
+func xmlName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+
+	for i, char := range name {
+		switch {
+		case char == '_' || unicode.IsLetter(char):
+		case i > 0 && (char == '-' || char == '.' || unicode.IsDigit(char) || unicode.Is(unicode.M, char)):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// xmlEncode encodes the given generic value, as returned
+// by codecValue, into an element with the given name.
+func xmlEncode(encoder *xml.Encoder, name string, value any) error {
+	start := xmlElement(name)
+
+	switch typed := value.(type) {
+	case nil:
+		return encoder.EncodeElement("", start)
+	case []any:
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+
+		for _, item := range typed {
+			if err := xmlEncode(encoder, "i", item); err != nil {
+				return err
+			}
+		}
+
+		return encoder.EncodeToken(start.End())
+	case map[string]any:
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+
+		keys := make([]string, 0, len(typed))
+
+		for key := range typed {
+			keys = append(keys, key)
+		}
+
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			if !xmlName(key) {
+				continue
+			}
+
+			if err := xmlEncode(encoder, key, typed[key]); err != nil {
+				return err
+			}
+		}
+
+		return encoder.EncodeToken(start.End())
+	}
+
+	return encoder.EncodeElement(value, start)
+}
+
+// xmlDecode decodes the current element into a generic value,
+// until its end element is found.
+func xmlDecode(decoder *xml.Decoder) (any, error) {
+	text := &strings.Builder{}
+	names := make([]string, 0)
+	values := make([]any, 0)
+
+	for {
+		token, err := decoder.Token()
+
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch typed := token.(type) {
+		case xml.CharData:
+			text.Write(typed)
+		case xml.StartElement:
+			value, err := xmlDecode(decoder)
+
+			if err != nil {
+				return nil, err
+			}
+
+			names = append(names, typed.Name.Local)
+			values = append(values, value)
+		case xml.EndElement:
+			if len(names) == 0 {
+				return text.String(), nil
+			}
+
+			if !slices.ContainsFunc(names, func(name string) bool { return name != "i" }) {
+				return values, nil
+			}
+
+			mapped := make(map[string]any, len(names))
+
+			for i, name := range names {
+				mapped[name] = values[i]
+			}
+
+			return mapped, nil
+		}
+	}
+}
+
+// problemXMLResponse returns the "application/problem+xml"
+// response of the given problem.
+func problemXMLResponse(problem Problem) Builder {
+	return Response(problem.Status).
+		Header("Content-Type", "application/problem+xml").
+		BodyWriter(func(writer http.ResponseWriter) {
+			encoded, err := xml.Marshal(problem)
+
+			if err != nil {
+				// The extension members are left out when they
+				// cannot be encoded, as the headers are sent.
+				encoded, _ = xml.Marshal(Problem{
+					Type:     problem.Type,
+					Title:    problem.Title,
+					Status:   problem.Status,
+					Detail:   problem.Detail,
+					Instance: problem.Instance,
+				})
+			}
+
+			_, _ = io.WriteString(writer, xml.Header)
+			_, _ = writer.Write(encoded)
+		})
+}
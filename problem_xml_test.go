@@ -0,0 +1,227 @@
+package akumu_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+)
+
+func TestProblemMarshalXML(t *testing.T) {
+	problem := akumu.Problem{
+		Type:     "https://example.com/probs/out-of-credit",
+		Title:    "You do not have enough credit.",
+		Status:   http.StatusForbidden,
+		Detail:   "Your current balance is 30, but that costs 50.",
+		Instance: "/account/12345/msgs/abc",
+	}.
+		With("balance", 30).
+		With("accounts", []string{"/account/12345", "/account/67890"})
+
+	encoded, err := xml.Marshal(problem)
+
+	if err != nil {
+		t.Fatalf("failed to marshal problem: %v", err)
+	}
+
+	expected := `<problem xmlns="urn:ietf:rfc:7807">` +
+		`<type>https://example.com/probs/out-of-credit</type>` +
+		`<title>You do not have enough credit.</title>` +
+		`<status>403</status>` +
+		`<detail>Your current balance is 30, but that costs 50.</detail>` +
+		`<instance>/account/12345/msgs/abc</instance>` +
+		`<accounts><i>/account/12345</i><i>/account/67890</i></accounts>` +
+		`<balance>30</balance>` +
+		`</problem>`
+
+	if string(encoded) != expected {
+		t.Fatalf("expected %s but got %s", expected, encoded)
+	}
+}
+
+func TestProblemMarshalXMLInvalidNames(t *testing.T) {
+	problem := akumu.Problem{Status: http.StatusBadRequest}.
+		With("valid_name-1.0", 1).
+		With("with space", 2).
+		With("pre:fix", 3).
+		With("1digit", 4).
+		With("XMLReserved", 5).
+		With("nested", map[string]any{"ok": 6, "not ok": 7})
+
+	encoded, err := xml.Marshal(problem)
+
+	if err != nil {
+		t.Fatalf("failed to marshal problem: %v", err)
+	}
+
+	expected := `<problem xmlns="urn:ietf:rfc:7807">` +
+		`<type></type>` +
+		`<title></title>` +
+		`<status>400</status>` +
+		`<detail></detail>` +
+		`<instance></instance>` +
+		`<nested><ok>6</ok></nested>` +
+		`<valid_name-1.0>1</valid_name-1.0>` +
+		`</problem>`
+
+	if string(encoded) != expected {
+		t.Fatalf("expected %s but got %s", expected, encoded)
+	}
+
+	var decoded akumu.Problem
+
+	if err := xml.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal problem: %v", err)
+	}
+}
+
+func TestProblemUnmarshalXML(t *testing.T) {
+	encoded := `<?xml version="1.0" encoding="UTF-8"?>
+<problem xmlns="urn:ietf:rfc:7807">
+  <type>https://example.com/probs/out-of-credit</type>
+  <title>You do not have enough credit.</title>
+  <status>403</status>
+  <balance>30</balance>
+  <accounts>
+    <i>/account/12345</i>
+    <i>/account/67890</i>
+  </accounts>
+  <limits>
+    <daily>10</daily>
+  </limits>
+</problem>`
+
+	var problem akumu.Problem
+
+	if err := xml.Unmarshal([]byte(encoded), &problem); err != nil {
+		t.Fatalf("failed to unmarshal problem: %v", err)
+	}
+
+	if expected := http.StatusForbidden; problem.Status != expected {
+		t.Fatalf("expected status %d but got %d", expected, problem.Status)
+	}
+
+	if expected := "You do not have enough credit."; problem.Title != expected {
+		t.Fatalf("expected title %s but got %s", expected, problem.Title)
+	}
+
+	if balance, _ := problem.Additional("balance"); balance != "30" {
+		t.Fatalf("expected balance 30 but got %v", balance)
+	}
+
+	accounts, _ := problem.Additional("accounts")
+
+	if expected := []any{"/account/12345", "/account/67890"}; !reflect.DeepEqual(accounts, expected) {
+		t.Fatalf("expected accounts %v but got %v", expected, accounts)
+	}
+
+	limits, _ := problem.Additional("limits")
+
+	if expected := map[string]any{"daily": "10"}; !reflect.DeepEqual(limits, expected) {
+		t.Fatalf("expected limits %v but got %v", expected, limits)
+	}
+}
+
+func TestProblemXMLResponse(t *testing.T) {
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+
+	request.Header.Add("Accept", "application/problem+xml")
+	response := akumu.Record(customProblemHandler, request)
+
+	if response.Code != http.StatusUnauthorized {
+		t.Fatalf("unexpected status code: %d", response.Code)
+	}
+
+	if expected := "application/problem+xml"; response.Header().Get("Content-Type") != expected {
+		t.Fatalf("expected content type %s but got %s", expected, response.Header().Get("Content-Type"))
+	}
+
+	if expected := xml.Header + `<problem xmlns="urn:ietf:rfc:7807">`; !strings.HasPrefix(response.Body.String(), expected) {
+		t.Fatalf("expected body to start with %s but got %s", expected, response.Body.String())
+	}
+
+	var problem akumu.Problem
+
+	if err := xml.Unmarshal(response.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("failed to unmarshal problem: %v", err)
+	}
+
+	if username, _ := problem.Additional("username"); username != "foobar" {
+		t.Fatalf("expected username foobar but got %v", username)
+	}
+}
+
+type XMLTestCounter struct {
+	calls *int
+}
+
+func (counter XMLTestCounter) MarshalJSON() ([]byte, error) {
+	*counter.calls++
+
+	return []byte(`"counted"`), nil
+}
+
+func TestProblemXMLResponseLazy(t *testing.T) {
+	tests := []struct {
+		accept string
+		calls  int
+	}{
+		{"application/problem+json", 1},
+		{"application/problem+xml", 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.accept, func(t *testing.T) {
+			calls := 0
+
+			request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+
+			request.Header.Add("Accept", test.accept)
+
+			handler := func(*http.Request) error {
+				return akumu.NewProblem(nil, http.StatusBadRequest).With("counter", XMLTestCounter{calls: &calls})
+			}
+
+			akumu.Record(handler, request)
+
+			if calls != test.calls {
+				t.Fatalf("expected %d encodings but got %d", test.calls, calls)
+			}
+		})
+	}
+}
+
+func TestProblemXMLResponseUnsupported(t *testing.T) {
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+
+	request.Header.Add("Accept", "application/problem+xml")
+
+	handler := func(*http.Request) error {
+		return akumu.NewProblem(nil, http.StatusBadRequest).With("channel", make(chan int))
+	}
+
+	response := akumu.Record(handler, request)
+
+	if response.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d but got %d", http.StatusBadRequest, response.Code)
+	}
+
+	if !strings.Contains(response.Body.String(), "<status>400</status>") || strings.Contains(response.Body.String(), "channel") {
+		t.Fatalf("expected the problem without its extension members but got %s", response.Body.String())
+	}
+}
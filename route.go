@@ -0,0 +1,217 @@
+package akumu
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Route represents a single route registration
+// made on a [Router], that is, a method and a
+// pattern bound to a [Handler].
+//
+// Routes are returned by the [Router] registration
+// methods and can be used to attach additional
+// information to them, such as a name.
+type Route struct {
+
+	// router stores the [Router] that was used
+	// to register the route.
+	router *Router
+
+	// method stores the http method the route
+	// was registered with.
+	method string
+
+	// pattern stores the full pattern of the route,
+	// already joined with any group prefix.
+	pattern string
+
+	// name stores the name of the route, if any.
+	// It's used to generate URLs using [Router.URL].
+	name string
+
+	// middlewares stores the number of middlewares that
+	// were wrapping the handler at registration time.
+	middlewares int
+
+	// redirect determines if the route is a trailing slash
+	// redirection route that was automatically registered.
+	redirect bool
+}
+
+// RouteInfo is a read-only snapshot of a [Route]
+// that is returned by [Router.Routes].
+//
+// It's useful to inspect the registered routes of
+// a [Router], for example, to print a route table.
+type RouteInfo struct {
+
+	// Method is the http method of the route.
+	Method string
+
+	// Pattern is the full pattern of the route, already
+	// joined with any prefix given by [Router.Group].
+	Pattern string
+
+	// Name is the name of the route, if any.
+	Name string
+
+	// Prefix is the prefix of the router (or sub-router)
+	// that was used to register the route.
+	Prefix string
+
+	// Middlewares is the number of middlewares that
+	// wrap the route's handler.
+	Middlewares int
+
+	// Redirect determines if the route is a trailing slash
+	// redirection route that was automatically registered
+	// by [Router.Method].
+	Redirect bool
+}
+
+// Routes is a list of [Route] that is returned
+// by registrations that bind more than one route
+// at once, such as [Router.Methods] or [Router.Any].
+type Routes []*Route
+
+var (
+	// ErrRouteNotFound determines that there's no
+	// route registered with the given name.
+	ErrRouteNotFound = errors.New("route not found")
+
+	// ErrRouteMissingParameter determines that a
+	// wildcard of the route pattern was not given
+	// a value.
+	ErrRouteMissingParameter = errors.New("route parameter is missing")
+
+	// ErrRouteExtraParameter determines that a
+	// parameter was given but the route pattern
+	// does not have such wildcard.
+	ErrRouteExtraParameter = errors.New("route parameter is not in the pattern")
+
+	// ErrRouteOddParameters determines that the
+	// parameters were not given as key-value pairs.
+	ErrRouteOddParameters = errors.New("route parameters must be key-value pairs")
+)
+
+// Method returns the http method of the route.
+func (route *Route) Method() string {
+	return route.method
+}
+
+// Pattern returns the full pattern of the route,
+// including any prefix given by [Router.Group].
+func (route *Route) Pattern() string {
+	return route.pattern
+}
+
+// Name sets the name of the route so that it can later
+// be used to generate URLs using [Router.URL].
+//
+// The same name can be used by many routes as long
+// as they all share the same pattern, which is useful
+// when the same path is registered with multiple methods.
+//
+// It panics if the name is already in use by a route
+// with a different pattern, just like [http.ServeMux]
+// does with conflicting patterns.
+func (route *Route) Name(name string) *Route {
+	if other, ok := route.router.route(name); ok && other.pattern != route.pattern {
+		panic(fmt.Sprintf(
+			"akumu: route name %q of pattern %q conflicts with pattern %q",
+			name,
+			route.pattern,
+			other.pattern,
+		))
+	}
+
+	route.name = name
+
+	return route
+}
+
+// Name sets the given name to all the routes.
+//
+// See [Route.Name] for more information.
+func (routes Routes) Name(name string) Routes {
+	for _, route := range routes {
+		route.Name(name)
+	}
+
+	return routes
+}
+
+// info returns the [RouteInfo] of the route.
+func (route *Route) info() RouteInfo {
+	return RouteInfo{
+		Method:      route.method,
+		Pattern:     route.pattern,
+		Name:        route.name,
+		Prefix:      route.router.pattern,
+		Middlewares: route.middlewares,
+		Redirect:    route.redirect,
+	}
+}
+
+// url builds the route's path by replacing its wildcards with
+// the given parameters, given as key-value pairs.
+func (route *Route) url(params ...string) (string, error) {
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("%w: %s", ErrRouteOddParameters, route.name)
+	}
+
+	values := make(map[string]string, len(params)/2)
+
+	for i := 0; i < len(params); i += 2 {
+		values[params[i]] = params[i+1]
+	}
+
+	segments := strings.Split(route.pattern, "/")
+
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+
+		if name == "$" {
+			segments[i] = ""
+			continue
+		}
+
+		rest := strings.HasSuffix(name, "...")
+		name = strings.TrimSuffix(name, "...")
+		value, ok := values[name]
+
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrRouteMissingParameter, name)
+		}
+
+		delete(values, name)
+
+		if !rest {
+			segments[i] = url.PathEscape(value)
+			continue
+		}
+
+		parts := strings.Split(value, "/")
+
+		for j, part := range parts {
+			parts[j] = url.PathEscape(part)
+		}
+
+		segments[i] = strings.Join(parts, "/")
+	}
+
+	for i := 0; i < len(params); i += 2 {
+		if _, ok := values[params[i]]; ok {
+			return "", fmt.Errorf("%w: %s", ErrRouteExtraParameter, params[i])
+		}
+	}
+
+	return strings.Join(segments, "/"), nil
+}
@@ -1,6 +1,7 @@
 package akumu
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -42,6 +43,41 @@ type Router struct {
 	// router. It already contains all the middlewares of
 	// the parent's [Router] if any.
 	middlewares []Middleware
+
+	// methods stores all the http methods that have been
+	// registered in the router. It's only used by the root
+	// [Router] and it's needed to resolve the allowed methods
+	// of a given path when the request method does not match.
+	methods []string
+
+	// routes stores all the routes that have been registered
+	// in the router. It's only used by the root [Router].
+	routes []*Route
+
+	// notFound stores the [Handler] that will be used whenever
+	// a request does not match any registered route. It's only
+	// used by the root [Router].
+	notFound Handler
+
+	// methodNotAllowed stores the [Handler] that will be used whenever
+	// a request path matches a registered route but its method does not.
+	// It's only used by the root [Router].
+	methodNotAllowed Handler
+}
+
+// PatternKey is used in the [http.Request]'s context to
+// store the pattern of the [Router] route that matched it.
+type PatternKey struct{}
+
+// RequestPattern returns the pattern of the [Router] route that
+// matched the given request, such as "GET /users/{id}", as stored in
+// its context by the [Router] before running the route middlewares.
+//
+// Returns an empty string if the request was not routed by a [Router].
+func RequestPattern(request *http.Request) string {
+	pattern, _ := request.Context().Value(PatternKey{}).(string)
+
+	return pattern
 }
 
 // NewRouter creates a new [Router] instance and
@@ -54,6 +90,11 @@ func NewRouter() *Router {
 		pattern:     "",
 		parent:      nil,
 		middlewares: make([]Middleware, 0),
+		methods:     make([]string, 0),
+		routes:      make([]*Route, 0),
+
+		notFound:         notFoundHandler,
+		methodNotAllowed: methodNotAllowedHandler,
 	}
 }
 
@@ -104,6 +145,17 @@ func (router *Router) mux() *http.ServeMux {
 	return router.native
 }
 
+// root returns the top-most [Router], that is, the one that
+// was created using [NewRouter]. Sub-routers share some state
+// with it, such as the registered methods.
+func (router *Router) root() *Router {
+	if router.parent != nil {
+		return router.parent.root()
+	}
+
+	return router
+}
+
 // wrap makes an [http.Handler] wrapped by the current routers'
 // middlewares. This means that the resulting [http.Handler] is
 // the same as first calling the router middlewares and then the
@@ -129,11 +181,16 @@ func (router *Router) Use(middlewares ...Middleware) {
 }
 
 // register adds the given pattern and handler to the actual native
-// router [http.ServeMux].
+// router [http.ServeMux]. The pattern is stored in the request's
+// context, on the [PatternKey] key, before running the handler.
 func (router *Router) register(pattern string, handler http.Handler) {
 	router.
 		mux().
-		Handle(pattern, handler)
+		Handle(pattern, http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			ctx := context.WithValue(request.Context(), PatternKey{}, pattern)
+
+			handler.ServeHTTP(writer, request.WithContext(ctx))
+		}))
 }
 
 // Method registers a new handler to the router with the given
@@ -158,8 +215,25 @@ func (router *Router) register(pattern string, handler http.Handler) {
 //   - [http.MethodConnect]
 //   - [http.MethodOptions]
 //   - [http.MethodTrace]
-func (router *Router) Method(method string, pattern string, handler Handler) {
+//
+// The returned [Route] can be used to further describe the
+// registration, for example, giving it a name with [Route.Name].
+func (router *Router) Method(method string, pattern string, handler Handler) *Route {
 	pattern = path.Join(router.pattern, pattern)
+	root := router.root()
+
+	if !slices.Contains(root.methods, method) {
+		root.methods = append(root.methods, method)
+	}
+
+	route := &Route{
+		router:      router,
+		method:      method,
+		pattern:     pattern,
+		middlewares: len(router.middlewares),
+	}
+
+	root.routes = append(root.routes, route)
 
 	if pattern == "/" {
 		router.register(
@@ -167,7 +241,7 @@ func (router *Router) Method(method string, pattern string, handler Handler) {
 			router.wrap(handler),
 		)
 
-		return
+		return route
 	}
 
 	if !strings.HasSuffix("/", pattern) && !strings.HasSuffix(pattern, "...}") {
@@ -178,12 +252,21 @@ func (router *Router) Method(method string, pattern string, handler Handler) {
 			fmt.Sprintf("%s %s/{$}", method, pattern),
 			router.redirect(pattern),
 		)
+
+		root.routes = append(root.routes, &Route{
+			router:   router,
+			method:   method,
+			pattern:  pattern + "/{$}",
+			redirect: true,
+		})
 	}
 
 	router.register(
 		fmt.Sprintf("%s %s", method, pattern),
 		router.wrap(handler),
 	)
+
+	return route
 }
 
 // redirect is a helper handler that takes care of redirecting
@@ -200,14 +283,18 @@ func (router *Router) redirect(path string) http.Handler {
 }
 
 // Methods allows binding multiple methods to the pattern and handler.
-func (router *Router) Methods(methods []string, pattern string, handler Handler) {
-	for _, method := range methods {
-		router.Method(method, pattern, handler)
+func (router *Router) Methods(methods []string, pattern string, handler Handler) Routes {
+	routes := make(Routes, len(methods))
+
+	for i, method := range methods {
+		routes[i] = router.Method(method, pattern, handler)
 	}
+
+	return routes
 }
 
 // Any registers all methods to the given pattern and handler.
-func (router *Router) Any(pattern string, handler Handler) {
+func (router *Router) Any(pattern string, handler Handler) Routes {
 	methods := []string{
 		http.MethodGet,
 		http.MethodHead,
@@ -220,72 +307,159 @@ func (router *Router) Any(pattern string, handler Handler) {
 		http.MethodTrace,
 	}
 
-	router.Methods(methods, pattern, handler)
+	return router.Methods(methods, pattern, handler)
 }
 
 // Get registers a new handler to the router using [Router.Method]
 // and using the [http.MethodGet] as the method parameter.
-func (router *Router) Get(pattern string, handler Handler) {
-	router.Method(http.MethodGet, pattern, handler)
+func (router *Router) Get(pattern string, handler Handler) *Route {
+	return router.Method(http.MethodGet, pattern, handler)
 }
 
 // Head registers a new handler to the router using [Router.Method]
 // and using the [http.MethodHead] as the method parameter.
-func (router *Router) Head(pattern string, handler Handler) {
-	router.Method(http.MethodHead, pattern, handler)
+func (router *Router) Head(pattern string, handler Handler) *Route {
+	return router.Method(http.MethodHead, pattern, handler)
 }
 
 // Post registers a new handler to the router using [Router.Method]
 // and using the [http.MethodPost] as the method parameter.
-func (router *Router) Post(pattern string, handler Handler) {
-	router.Method(http.MethodPost, pattern, handler)
+func (router *Router) Post(pattern string, handler Handler) *Route {
+	return router.Method(http.MethodPost, pattern, handler)
 }
 
 // Put registers a new handler to the router using [Router.Method]
 // and using the [http.MethodPut] as the method parameter.
-func (router *Router) Put(pattern string, handler Handler) {
-	router.Method(http.MethodPut, pattern, handler)
+func (router *Router) Put(pattern string, handler Handler) *Route {
+	return router.Method(http.MethodPut, pattern, handler)
 }
 
 // Patch registers a new handler to the router using [Router.Method]
 // and using the [http.MethodPatch] as the method parameter.
-func (router *Router) Patch(pattern string, handler Handler) {
-	router.Method(http.MethodPatch, pattern, handler)
+func (router *Router) Patch(pattern string, handler Handler) *Route {
+	return router.Method(http.MethodPatch, pattern, handler)
 }
 
 // Delete registers a new handler to the router using [Router.Method]
 // and using the [http.MethodDelete] as the method parameter.
-func (router *Router) Delete(pattern string, handler Handler) {
-	router.Method(http.MethodDelete, pattern, handler)
+func (router *Router) Delete(pattern string, handler Handler) *Route {
+	return router.Method(http.MethodDelete, pattern, handler)
 }
 
 // Connect registers a new handler to the router using [Router.Method]
 // and using the [http.MethodConnect] as the method parameter.
-func (router *Router) Connect(pattern string, handler Handler) {
-	router.Method(http.MethodConnect, pattern, handler)
+func (router *Router) Connect(pattern string, handler Handler) *Route {
+	return router.Method(http.MethodConnect, pattern, handler)
 }
 
 // Options registers a new handler to the router using [Router.Method]
 // and using the [http.MethodOptions] as the method parameter.
-func (router *Router) Options(pattern string, handler Handler) {
-	router.Method(http.MethodOptions, pattern, handler)
+func (router *Router) Options(pattern string, handler Handler) *Route {
+	return router.Method(http.MethodOptions, pattern, handler)
 }
 
 // Trace registers a new handler to the router using [Router.Method]
 // and using the [http.MethodTrace] as the method parameter.
-func (router *Router) Trace(pattern string, handler Handler) {
-	router.Method(http.MethodTrace, pattern, handler)
+func (router *Router) Trace(pattern string, handler Handler) *Route {
+	return router.Method(http.MethodTrace, pattern, handler)
 }
 
 // ServeHTTP is the method that will make the router implement
 // the [http.Handler] interface, making it possible to be used
 // as a handler in places like [http.Server].
+//
+// Whenever the request path is registered but not with the request's
+// method, the router responds using the [Router.MethodNotAllowed] handler
+// with the "Allow" header set to the methods that are registered for
+// that path. If no route matches at all, the [Router.NotFound] handler
+// is used instead.
 func (router *Router) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if _, pattern := router.mux().Handler(request); pattern == "" {
+		root := router.root()
+
+		if allowed := router.allowed(request); len(allowed) > 0 {
+			writer.Header().Set("Allow", strings.Join(allowed, ", "))
+			root.
+				wrap(root.methodNotAllowed).
+				ServeHTTP(writer, request)
+
+			return
+		}
+
+		root.
+			wrap(root.notFound).
+			ServeHTTP(writer, request)
+
+		return
+	}
+
 	router.
-		native.
+		mux().
 		ServeHTTP(writer, request)
 }
 
+// NotFound sets the [Handler] that will respond to any request that
+// does not match a registered route.
+//
+// The handler is wrapped with the root router's middlewares at the time
+// the request is served, so things like the [ProblemControls] are respected.
+//
+// By default, a [Problem] with [http.StatusNotFound] is returned.
+func (router *Router) NotFound(handler Handler) {
+	router.root().notFound = handler
+}
+
+// MethodNotAllowed sets the [Handler] that will respond to any request
+// whose path matches a registered route but whose method does not.
+//
+// The "Allow" header is already set on the response by the time the
+// handler runs. Just like [Router.NotFound], the handler is wrapped with
+// the root router's middlewares at the time the request is served.
+//
+// By default, a [Problem] with [http.StatusMethodNotAllowed] is returned.
+func (router *Router) MethodNotAllowed(handler Handler) {
+	router.root().methodNotAllowed = handler
+}
+
+// allowed returns the sorted list of registered methods that
+// would match the given [http.Request] path. An empty list
+// means the path is not registered with any method.
+//
+// Keep in mind that, just like [http.ServeMux] does, the
+// [http.MethodHead] is allowed whenever [http.MethodGet] is.
+func (router *Router) allowed(request *http.Request) []string {
+	allowed := make([]string, 0)
+
+	for _, method := range router.root().methods {
+		clone := request.Clone(request.Context())
+		clone.Method = method
+
+		if _, pattern := router.mux().Handler(clone); pattern != "" {
+			allowed = append(allowed, method)
+		}
+	}
+
+	if slices.Contains(allowed, http.MethodGet) && !slices.Contains(allowed, http.MethodHead) {
+		allowed = append(allowed, http.MethodHead)
+	}
+
+	slices.Sort(allowed)
+
+	return allowed
+}
+
+// notFoundHandler is the default [Handler] used by the [Router]
+// whenever a request does not match any route.
+func notFoundHandler(request *http.Request) error {
+	return NewProblem(nil, http.StatusNotFound)
+}
+
+// methodNotAllowedHandler is the default [Handler] used by the [Router]
+// whenever a request path matches but its method does not.
+func methodNotAllowedHandler(request *http.Request) error {
+	return NewProblem(nil, http.StatusMethodNotAllowed)
+}
+
 // Has reports whether the given pattern is registered in the router
 // with the given method.
 //
@@ -334,6 +508,63 @@ func (router *Router) HandlerMatch(request *http.Request) (http.Handler, bool) {
 	return nil, false
 }
 
+// route returns the first [Route] registered with the given name.
+// The second return value determines if the [Route] was found or not.
+func (router *Router) route(name string) (*Route, bool) {
+	if name == "" {
+		return nil, false
+	}
+
+	for _, route := range router.root().routes {
+		if route.name == name {
+			return route, true
+		}
+	}
+
+	return nil, false
+}
+
+// Routes returns a [RouteInfo] for every registration made on
+// the router, including the ones made by sub-routers and the
+// trailing slash redirection routes that are automatically
+// registered by [Router.Method].
+//
+// Routes are returned in the same order they were registered, with
+// each redirection route right after the route it redirects to.
+func (router *Router) Routes() []RouteInfo {
+	routes := router.root().routes
+	infos := make([]RouteInfo, len(routes))
+
+	for i, route := range routes {
+		infos[i] = route.info()
+	}
+
+	return infos
+}
+
+// URL generates the path of the route registered with the given name,
+// replacing its wildcards with the given parameters. Parameters must be
+// given as key-value pairs, for example:
+//
+//	router.Get("/users/{user}/files/{path...}", handler).Name("files")
+//	router.URL("files", "user", "10", "path", "docs/cv.pdf")
+//	// "/users/10/files/docs/cv.pdf"
+//
+// Group prefixes are already part of the route pattern, so they
+// are respected as well.
+//
+// An error is returned if the route is not found, if a wildcard
+// is missing its value or if a parameter is not part of the pattern.
+func (router *Router) URL(name string, params ...string) (string, error) {
+	route, ok := router.route(name)
+
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrRouteNotFound, name)
+	}
+
+	return route.url(params...)
+}
+
 // Record returns a [httptest.ResponseRecorder] that can be used to inspect what
 // the given http request would have returned as a response.
 //
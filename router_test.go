@@ -1,6 +1,7 @@
 package akumu_test
 
 import (
+	"errors"
 	"net/http"
 	"testing"
 
@@ -104,3 +105,308 @@ func TestRouterHandler(t *testing.T) {
 		t.Fatalf("expected status code %d but got %d", expected, response.Code)
 	}
 }
+
+func TestRouterMethodNotAllowed(t *testing.T) {
+	router := akumu.NewRouter()
+
+	router.Get("/foo", func(request *http.Request) error {
+		return akumu.Response(http.StatusOK)
+	})
+
+	router.Post("/foo", func(request *http.Request) error {
+		return akumu.Response(http.StatusOK)
+	})
+
+	request, err := http.NewRequest(http.MethodDelete, "/foo", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request.Header.Add("Accept", "application/problem+json")
+
+	response := router.Record(request)
+
+	if expected := http.StatusMethodNotAllowed; response.Code != expected {
+		t.Fatalf("expected status code %d but got %d", expected, response.Code)
+	}
+
+	if expected, allow := "GET, HEAD, POST", response.Header().Get("Allow"); allow != expected {
+		t.Fatalf("expected allow header '%s' but got '%s'", expected, allow)
+	}
+
+	if expected, media := "application/problem+json", response.Header().Get("Content-Type"); media != expected {
+		t.Fatalf("expected content type '%s' but got '%s'", expected, media)
+	}
+}
+
+func TestRouterMethodNotAllowedUsesMiddlewares(t *testing.T) {
+	router := akumu.NewRouter()
+
+	router.Use(func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("X-Middleware", "true")
+			handler.ServeHTTP(writer, request)
+		})
+	})
+
+	router.Get("/foo", func(request *http.Request) error {
+		return akumu.Response(http.StatusOK)
+	})
+
+	request, err := http.NewRequest(http.MethodPut, "/foo", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	response := router.Record(request)
+
+	if expected := http.StatusMethodNotAllowed; response.Code != expected {
+		t.Fatalf("expected status code %d but got %d", expected, response.Code)
+	}
+
+	if value := response.Header().Get("X-Middleware"); value != "true" {
+		t.Fatalf("expected middleware to run on method not allowed responses")
+	}
+}
+
+func TestRouterNotFound(t *testing.T) {
+	router := akumu.NewRouter()
+
+	router.Get("/foo", func(request *http.Request) error {
+		return akumu.Response(http.StatusOK)
+	})
+
+	request, err := http.NewRequest(http.MethodGet, "/bar", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request.Header.Add("Accept", "application/problem+json")
+
+	response := router.Record(request)
+
+	if expected := http.StatusNotFound; response.Code != expected {
+		t.Fatalf("expected status code %d but got %d", expected, response.Code)
+	}
+
+	if expected, media := "application/problem+json", response.Header().Get("Content-Type"); media != expected {
+		t.Fatalf("expected content type '%s' but got '%s'", expected, media)
+	}
+}
+
+func TestRouterCustomNotFound(t *testing.T) {
+	router := akumu.NewRouter()
+
+	router.NotFound(func(request *http.Request) error {
+		return akumu.Response(http.StatusTeapot)
+	})
+
+	request, err := http.NewRequest(http.MethodGet, "/bar", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	response := router.Record(request)
+
+	if expected := http.StatusTeapot; response.Code != expected {
+		t.Fatalf("expected status code %d but got %d", expected, response.Code)
+	}
+}
+
+func TestRouterCustomMethodNotAllowed(t *testing.T) {
+	router := akumu.NewRouter()
+
+	router.MethodNotAllowed(func(request *http.Request) error {
+		return akumu.Response(http.StatusTeapot)
+	})
+
+	router.Post("/foo", func(request *http.Request) error {
+		return akumu.Response(http.StatusOK)
+	})
+
+	request, err := http.NewRequest(http.MethodGet, "/foo", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	response := router.Record(request)
+
+	if expected := http.StatusTeapot; response.Code != expected {
+		t.Fatalf("expected status code %d but got %d", expected, response.Code)
+	}
+
+	if expected, allow := "POST", response.Header().Get("Allow"); allow != expected {
+		t.Fatalf("expected allow header '%s' but got '%s'", expected, allow)
+	}
+}
+
+func TestRouterURL(t *testing.T) {
+	router := akumu.NewRouter()
+
+	router.Group("/users", func(router *akumu.Router) {
+		router.Get("/{user}", func(request *http.Request) error {
+			return akumu.Response(http.StatusOK)
+		}).Name("users.show")
+
+		router.Get("/{user}/files/{path...}", func(request *http.Request) error {
+			return akumu.Response(http.StatusOK)
+		}).Name("users.files")
+	})
+
+	url, err := router.URL("users.show", "user", "john doe")
+
+	if err != nil {
+		t.Fatalf("failed to generate url: %v", err)
+	}
+
+	if expected := "/users/john%20doe"; url != expected {
+		t.Fatalf("expected url '%s' but got '%s'", expected, url)
+	}
+
+	url, err = router.URL("users.files", "user", "10", "path", "docs/cv.pdf")
+
+	if err != nil {
+		t.Fatalf("failed to generate url: %v", err)
+	}
+
+	if expected := "/users/10/files/docs/cv.pdf"; url != expected {
+		t.Fatalf("expected url '%s' but got '%s'", expected, url)
+	}
+
+	if !router.Has(http.MethodGet, url) {
+		t.Fatalf("router should have the %s route", url)
+	}
+}
+
+func TestRouterURLErrors(t *testing.T) {
+	router := akumu.NewRouter()
+
+	router.Any("/users/{user}", func(request *http.Request) error {
+		return akumu.Response(http.StatusOK)
+	}).Name("users.show")
+
+	if _, err := router.URL("unknown"); !errors.Is(err, akumu.ErrRouteNotFound) {
+		t.Fatalf("expected error '%v' but got '%v'", akumu.ErrRouteNotFound, err)
+	}
+
+	if _, err := router.URL("users.show"); !errors.Is(err, akumu.ErrRouteMissingParameter) {
+		t.Fatalf("expected error '%v' but got '%v'", akumu.ErrRouteMissingParameter, err)
+	}
+
+	if _, err := router.URL("users.show", "user", "1", "foo", "bar"); !errors.Is(err, akumu.ErrRouteExtraParameter) {
+		t.Fatalf("expected error '%v' but got '%v'", akumu.ErrRouteExtraParameter, err)
+	}
+
+	if _, err := router.URL("users.show", "user"); !errors.Is(err, akumu.ErrRouteOddParameters) {
+		t.Fatalf("expected error '%v' but got '%v'", akumu.ErrRouteOddParameters, err)
+	}
+}
+
+func TestRouterRoutes(t *testing.T) {
+	router := akumu.NewRouter()
+	middleware := func(handler http.Handler) http.Handler {
+		return handler
+	}
+
+	router.Use(middleware)
+
+	router.Get("/", func(request *http.Request) error {
+		return akumu.Response(http.StatusOK)
+	}).Name("home")
+
+	router.Group("/users", func(router *akumu.Router) {
+		router.
+			With(middleware).
+			Post("/{user}", func(request *http.Request) error {
+				return akumu.Response(http.StatusOK)
+			})
+	})
+
+	routes := router.Routes()
+
+	if expected := 3; len(routes) != expected {
+		t.Fatalf("expected %d routes but got %d", expected, len(routes))
+	}
+
+	home := akumu.RouteInfo{
+		Method:      http.MethodGet,
+		Pattern:     "/",
+		Name:        "home",
+		Prefix:      "",
+		Middlewares: 1,
+		Redirect:    false,
+	}
+
+	if routes[0] != home {
+		t.Fatalf("expected route %+v but got %+v", home, routes[0])
+	}
+
+	redirect := akumu.RouteInfo{
+		Method:      http.MethodPost,
+		Pattern:     "/users/{user}/{$}",
+		Prefix:      "/users",
+		Middlewares: 0,
+		Redirect:    true,
+	}
+
+	if routes[2] != redirect {
+		t.Fatalf("expected route %+v but got %+v", redirect, routes[2])
+	}
+
+	user := akumu.RouteInfo{
+		Method:      http.MethodPost,
+		Pattern:     "/users/{user}",
+		Prefix:      "/users",
+		Middlewares: 2,
+		Redirect:    false,
+	}
+
+	if routes[1] != user {
+		t.Fatalf("expected route %+v but got %+v", user, routes[1])
+	}
+}
+
+func TestRequestPattern(t *testing.T) {
+	patterns := make([]string, 0)
+	router := akumu.NewRouter()
+
+	router.Use(func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			patterns = append(patterns, akumu.RequestPattern(request))
+			handler.ServeHTTP(writer, request)
+		})
+	})
+
+	router.Get("/users/{id}", func(request *http.Request) error {
+		patterns = append(patterns, akumu.RequestPattern(request))
+
+		if expected := "10"; request.PathValue("id") != expected {
+			t.Fatalf("expected id %s but got %s", expected, request.PathValue("id"))
+		}
+
+		return akumu.Response(http.StatusOK)
+	})
+
+	request, err := http.NewRequest(http.MethodGet, "/users/10", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	router.Record(request)
+
+	expected := "GET /users/{id}"
+
+	if len(patterns) != 2 || patterns[0] != expected || patterns[1] != expected {
+		t.Fatalf("expected patterns %s but got %v", expected, patterns)
+	}
+
+	if pattern := akumu.RequestPattern(request); pattern != "" {
+		t.Fatalf("expected no pattern outside the router but got %s", pattern)
+	}
+}
@@ -0,0 +1,112 @@
+package akumu
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// StackFrame is a single frame of a goroutine stack,
+// as captured by [runtime/debug.Stack].
+type StackFrame struct {
+
+	// Function is the fully qualified
+	// name of the frame's function.
+	Function string `json:"function"`
+
+	// File is the path of the source file
+	// where the frame's function is defined.
+	File string `json:"file"`
+
+	// Line is the line of the source
+	// file that's being executed.
+	Line int `json:"line"`
+}
+
+// StackTracer is implemented by the errors that carry the stack
+// of the goroutine they were created at, such as [ErrPanic].
+//
+// The stack frames of those errors are included in the errors
+// tree of a [Problem], as returned by [Problem.Errors].
+type StackTracer interface {
+	StackFrames() []StackFrame
+}
+
+// ParseStack parses the structured frames of the given goroutine
+// stack, as formatted by [runtime/debug.Stack].
+//
+// When the stack comes from a panicking goroutine, the frames of
+// the panic machinery, including the deferred functions that run
+// due to the panic, are skipped.
+func ParseStack(stack []byte) []StackFrame {
+	frames := make([]StackFrame, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(stack))
+	function := ""
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "goroutine ") || strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "\t") {
+			function = stackFunction(line)
+
+			continue
+		}
+
+		file, number := stackLocation(line)
+
+		if function == "panic" {
+			frames = frames[:0]
+			function = ""
+
+			continue
+		}
+
+		frames = append(frames, StackFrame{
+			Function: function,
+			File:     file,
+			Line:     number,
+		})
+	}
+
+	return frames
+}
+
+// stackFunction returns the name of the function
+// of the given function line of a goroutine stack.
+func stackFunction(line string) string {
+	if name, ok := strings.CutPrefix(line, "created by "); ok {
+		name, _, _ = strings.Cut(name, " in goroutine ")
+
+		return name
+	}
+
+	if index := strings.LastIndex(line, "("); index > 0 {
+		return line[:index]
+	}
+
+	return line
+}
+
+// stackLocation returns the source file and line
+// of the given location line of a goroutine stack.
+func stackLocation(line string) (string, int) {
+	location, _, _ := strings.Cut(strings.TrimSpace(line), " +0x")
+	index := strings.LastIndex(location, ":")
+
+	if index < 0 {
+		return location, 0
+	}
+
+	number, err := strconv.Atoi(location[index+1:])
+
+	if err != nil {
+		return location, 0
+	}
+
+	return location[:index], number
+}
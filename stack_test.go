@@ -0,0 +1,82 @@
+package akumu_test
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+)
+
+func TestParseStack(t *testing.T) {
+	stack := []byte("goroutine 7 [running]:\n" +
+		"main.(*Server).handle(0x1, {0x2, 0x3})\n" +
+		"\t/app/server.go:42 +0x1d\n" +
+		"created by main.main in goroutine 1\n" +
+		"\t/app/main.go:10 +0x5e\n")
+
+	frames := akumu.ParseStack(stack)
+
+	if expected := 2; len(frames) != expected {
+		t.Fatalf("expected %d frames but got %d", expected, len(frames))
+	}
+
+	expected := akumu.StackFrame{Function: "main.(*Server).handle", File: "/app/server.go", Line: 42}
+
+	if frames[0] != expected {
+		t.Fatalf("expected frame %+v but got %+v", expected, frames[0])
+	}
+
+	if expected := "main.main"; frames[1].Function != expected {
+		t.Fatalf("expected function '%s' but got '%s'", expected, frames[1].Function)
+	}
+}
+
+func TestParseStackSkipsPanic(t *testing.T) {
+	var frames []akumu.StackFrame
+
+	func() {
+		defer func() {
+			recover()
+			frames = akumu.ParseStack(debug.Stack())
+		}()
+
+		panic("boom")
+	}()
+
+	if len(frames) == 0 {
+		t.Fatalf("expected frames but got none")
+	}
+
+	if !strings.Contains(frames[0].Function, "TestParseStackSkipsPanic") {
+		t.Fatalf("expected first frame to be the panicking function but got '%s'", frames[0].Function)
+	}
+
+	if !strings.HasSuffix(frames[0].File, "stack_test.go") {
+		t.Fatalf("expected file to be the test file but got '%s'", frames[0].File)
+	}
+}
+
+func TestProblemErrorsStack(t *testing.T) {
+	frames := []akumu.StackFrame{{Function: "main.main", File: "/app/main.go", Line: 10}}
+	problem := akumu.NewProblem(akumu.ErrPanic{Value: "boom", Err: errors.New("boom"), Stack: frames}, http.StatusInternalServerError)
+	traces := problem.Errors()
+
+	if expected := 1; len(traces) != expected {
+		t.Fatalf("expected %d errors but got %d", expected, len(traces))
+	}
+
+	if expected := 1; len(traces[0].Stack) != expected {
+		t.Fatalf("expected %d frames but got %d", expected, len(traces[0].Stack))
+	}
+
+	if traces[0].Stack[0] != frames[0] {
+		t.Fatalf("expected frame %+v but got %+v", frames[0], traces[0].Stack[0])
+	}
+
+	if expected := "boom"; len(traces[0].Causes) != 1 || traces[0].Causes[0].Message != expected {
+		t.Fatalf("expected cause '%s' but got %+v", expected, traces[0].Causes)
+	}
+}
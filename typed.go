@@ -0,0 +1,79 @@
+package akumu
+
+import (
+	"context"
+	"net/http"
+)
+
+// TypedHandler is a function that takes care of a request
+// using typed input and output values instead of working
+// with the [http.Request] directly.
+//
+// Use [Typed] to transform it into a [Handler].
+type TypedHandler[In any, Out any] func(ctx context.Context, in In) (Out, error)
+
+// Typed transforms a [TypedHandler] into a [Handler].
+//
+// The input is decoded from the request as follows:
+//  1. The body is decoded as JSON using [JSONWith], if any. Only the
+//     "application/json" and "application/*+json" media types are allowed.
+//  2. Struct fields are filled using the request parameters, the same way [Bind] does.
+//
+// The input is then validated using [Validate].
+//
+// Decoding failures are responded as a [Problem] with [http.StatusBadRequest],
+// or [http.StatusUnsupportedMediaType] if the body is not JSON.
+//
+// The output is responded with a [http.StatusOK] using [Builder.Negotiate],
+// meaning it's encoded with the registered [Codec] that the request accepts,
+// or responded as a [Problem] with [http.StatusNotAcceptable] if there's none.
+// Errors returned by the handler are handled the same way a [Handler] error is.
+func Typed[In any, Out any](handler TypedHandler[In, Out]) Handler {
+	return func(request *http.Request) error {
+		in, err := decodeTyped[In](request)
+
+		if err != nil {
+			return err
+		}
+
+		if err := Validate(in); err != nil {
+			return err
+		}
+
+		out, err := handler(request.Context(), in)
+
+		if err != nil {
+			return err
+		}
+
+		return Response(http.StatusOK).Negotiate(out)
+	}
+}
+
+// hasBody reports whether the given request has a body to decode.
+func hasBody(request *http.Request) bool {
+	return request.Body != nil && request.Body != http.NoBody && request.ContentLength != 0
+}
+
+// decodeTyped decodes the input of a [Typed] handler from the request.
+func decodeTyped[In any](request *http.Request) (In, error) {
+	in := *new(In)
+
+	if hasBody(request) {
+		decoded, err := JSONWith[In](request, JSONOptions{
+			MediaTypes: []string{"application/json", "application/*+json"},
+		})
+
+		if err != nil {
+			return in, err
+		}
+
+		in = decoded
+	}
+
+	if err := bind(request, &in); err != nil {
+		return in, err
+	}
+
+	return in, nil
+}
@@ -0,0 +1,112 @@
+package akumu_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+)
+
+type TypedTestInput struct {
+	ID    int    `json:"-" path:"id"`
+	Page  int    `json:"-" query:"page"`
+	Name  string `json:"name"`
+	Admin bool   `json:"-" query:"admin"`
+}
+
+func (input TypedTestInput) Validate() error {
+	if input.Name == "invalid" {
+		return errors.New("name is invalid")
+	}
+
+	return nil
+}
+
+type TypedTestOutput struct {
+	ID    int    `json:"id"`
+	Page  int    `json:"page"`
+	Name  string `json:"name"`
+	Admin bool   `json:"admin"`
+}
+
+func typedTestHandler(ctx context.Context, in TypedTestInput) (TypedTestOutput, error) {
+	return TypedTestOutput(in), nil
+}
+
+func typedTestRouter() *akumu.Router {
+	router := akumu.NewRouter()
+	router.Post("/users/{id}", akumu.Typed(typedTestHandler))
+
+	return router
+}
+
+func TestTyped(t *testing.T) {
+	request, err := http.NewRequest(http.MethodPost, "/users/10?page=2&admin=true", strings.NewReader(`{"name":"john"}`))
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response := typedTestRouter().Record(request)
+
+	if expected := http.StatusOK; response.Code != expected {
+		t.Fatalf("expected status code %d but got %d", expected, response.Code)
+	}
+
+	var output TypedTestOutput
+
+	if err := json.NewDecoder(response.Body).Decode(&output); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	expected := TypedTestOutput{ID: 10, Page: 2, Name: "john", Admin: true}
+
+	if output != expected {
+		t.Fatalf("expected output %+v but got %+v", expected, output)
+	}
+}
+
+func TestTypedFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		body   string
+		media  string
+		accept string
+		status int
+	}{
+		{"bad query", "/users/10?page=foo", `{"name":"john"}`, "application/json", "", http.StatusBadRequest},
+		{"bad body", "/users/10", `{"name":10}`, "application/json", "", http.StatusBadRequest},
+		{"bad media", "/users/10", `name=john`, "application/x-www-form-urlencoded", "", http.StatusUnsupportedMediaType},
+		{"invalid", "/users/10", `{"name":"invalid"}`, "application/json", "", http.StatusUnprocessableEntity},
+		{"not acceptable", "/users/10", `{"name":"john"}`, "application/json", "text/html", http.StatusNotAcceptable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodPost, test.url, strings.NewReader(test.body))
+
+			if err != nil {
+				t.Fatalf("failed to create http request: %v", err)
+			}
+
+			request.Header.Set("Content-Type", test.media)
+
+			if test.accept != "" {
+				request.Header.Set("Accept", test.accept)
+			}
+
+			response := typedTestRouter().Record(request)
+
+			if response.Code != test.status {
+				t.Fatalf("expected status code %d but got %d", test.status, response.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package akumu
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// File is a file that has been uploaded using a multipart
+// request and that has been stored in a temporary file.
+//
+// Use [Upload] to bind uploaded files into structs.
+type File struct {
+
+	// Field is the name of the multipart form
+	// field that contained the file.
+	Field string
+
+	// Filename is the name of the file, as given
+	// by the client. It must not be trusted.
+	Filename string
+
+	// MediaType is the media type of the file, sniffed
+	// from its contents using [http.DetectContentType].
+	MediaType string
+
+	// Size is the size of the file in bytes.
+	Size int64
+
+	// Header is the multipart header of the file part.
+	Header textproto.MIMEHeader
+
+	// path stores the path of the temporary
+	// file where the contents are stored.
+	path string
+}
+
+// UploadOptions are the options that [Upload] uses
+// to read multipart requests.
+type UploadOptions struct {
+
+	// MaxFileBytes is the maximum size in bytes of each file.
+	// Zero means there's no limit.
+	MaxFileBytes int64
+
+	// MaxBytes is the maximum size in bytes of all the parts
+	// combined. Zero means there's no limit. Non-file values are
+	// always limited to [UploadMaxValueBytes] as well.
+	MaxBytes int64
+
+	// MediaTypes are the media types that the sniffed file
+	// contents must match, if any. Wildcards are allowed
+	// using the [path.Match] syntax, for example, "image/*".
+	MediaTypes []string
+
+	// Dir is the directory where the temporary files
+	// are stored. It defaults to [os.TempDir].
+	Dir string
+}
+
+// UploadMaxValueBytes is the maximum size in bytes that all
+// the non-file values of a multipart request can take in memory,
+// regardless of the MaxBytes limit of [UploadOptions].
+const UploadMaxValueBytes = 10 << 20
+
+// fileType is the [reflect.Type] of [File].
+var fileType = reflect.TypeFor[File]()
+
+var (
+	// ErrUploadNotMultipart determines that the request
+	// is not a "multipart/form-data" request.
+	ErrUploadNotMultipart = errors.New("request is not multipart")
+
+	// ErrUploadFileTooLarge determines that an uploaded
+	// file exceeds the maximum allowed size.
+	ErrUploadFileTooLarge = errors.New("uploaded file is too large")
+
+	// ErrUploadTooLarge determines that the multipart
+	// request exceeds the maximum allowed size.
+	ErrUploadTooLarge = errors.New("multipart request is too large")
+
+	// ErrUploadUnsupportedMediaType determines that an
+	// uploaded file has a media type that is not allowed.
+	ErrUploadUnsupportedMediaType = errors.New("uploaded file has an unsupported media type")
+)
+
+// Open opens the uploaded file for reading.
+func (file File) Open() (*os.File, error) {
+	return os.Open(file.path)
+}
+
+// Remove removes the temporary file where the
+// uploaded contents are stored.
+func (file File) Remove() error {
+	return os.Remove(file.path)
+}
+
+// upload stores the state of a multipart request that
+// is being streamed.
+type upload struct {
+	options UploadOptions
+	values  map[string][]string
+	files   map[string][]File
+
+	// total stores the bytes read from all the parts
+	// while memory stores only the bytes of the non-file parts.
+	total  int64
+	memory int64
+}
+
+// Upload streams the multipart request parts into a new `T` struct.
+//
+// Parts are read one by one using [http.Request.MultipartReader],
+// meaning that the request is never buffered into memory as a whole.
+// File parts are streamed into temporary files while the rest of the
+// values are kept in memory.
+//
+// Struct fields are bound using the following tags:
+//   - `file:"name"` binds the uploaded files of that field into
+//     a [File], a *[File] or a []File.
+//   - `form:"name"` binds the values of that field, the same way [Bind] does.
+//   - Any other tag understood by [Bind], such as `query:"name"`.
+//
+// Temporary files are removed once the [Handler] serving the request
+// has responded. When the request is not served by a [Handler], they
+// are removed once the request context is done instead, which happens
+// after the [http.Handler] returns when using [http.Server]. They may
+// also be removed earlier using [File.Remove].
+//
+// Limit violations are returned as a [Problem] with [http.StatusRequestEntityTooLarge]
+// while files with a media type that is not allowed, or requests that are not
+// "multipart/form-data", return a [Problem] with [http.StatusUnsupportedMediaType].
+func Upload[T any](request *http.Request, options UploadOptions) (T, error) {
+	result := *new(T)
+
+	state := &upload{
+		options: options,
+		values:  make(map[string][]string),
+		files:   make(map[string][]File),
+	}
+
+	if err := state.read(request); err != nil {
+		state.remove()
+
+		return result, err
+	}
+
+	if !cleanup(request, state.remove) {
+		context.AfterFunc(request.Context(), state.remove)
+	}
+
+	err := bindWith(&result, func(source string, name string) ([]string, error) {
+		if source == "form" {
+			return state.values[name], nil
+		}
+
+		return bindValues(request, source, name)
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	uploadFiles(reflect.ValueOf(&result).Elem(), state.files)
+
+	return result, nil
+}
+
+// read streams all the parts of the multipart request.
+func (state *upload) read(request *http.Request) error {
+	media, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+
+	if media != "multipart/form-data" {
+		return Problem{
+			Detail: "The request body must be multipart/form-data.",
+			Status: http.StatusUnsupportedMediaType,
+		}.WithError(fmt.Errorf("%w: %q", ErrUploadNotMultipart, media))
+	}
+
+	reader, err := request.MultipartReader()
+
+	if err != nil {
+		return NewProblem(err, http.StatusBadRequest)
+	}
+
+	for {
+		part, err := reader.NextPart()
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return NewProblem(err, http.StatusBadRequest)
+		}
+
+		if part.FormName() == "" {
+			continue
+		}
+
+		if part.FileName() == "" {
+			err = state.value(part)
+		} else {
+			err = state.file(part)
+		}
+
+		part.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// limit returns the remaining bytes that can still be read
+// and the error to use when the limit is exceeded.
+//
+// Non-file parts are limited by both the MaxBytes limit and
+// [UploadMaxValueBytes], using whichever is reached first.
+func (state *upload) limit(file bool) (int64, error) {
+	remaining, limitErr := int64(-1), error(nil)
+
+	if state.options.MaxBytes > 0 {
+		remaining = state.options.MaxBytes - state.total
+		limitErr = uploadTooLarge(ErrUploadTooLarge, state.options.MaxBytes)
+	}
+
+	if !file && (remaining < 0 || UploadMaxValueBytes-state.memory < remaining) {
+		remaining = UploadMaxValueBytes - state.memory
+		limitErr = uploadTooLarge(ErrUploadTooLarge, UploadMaxValueBytes)
+	}
+
+	return remaining, limitErr
+}
+
+// value reads a non-file part into memory.
+func (state *upload) value(part *multipart.Part) error {
+	remaining, limitErr := state.limit(false)
+	value, err := io.ReadAll(io.LimitReader(part, remaining+1))
+
+	if err != nil {
+		return NewProblem(err, http.StatusBadRequest)
+	}
+
+	if int64(len(value)) > remaining {
+		return limitErr
+	}
+
+	state.total += int64(len(value))
+	state.memory += int64(len(value))
+	state.values[part.FormName()] = append(state.values[part.FormName()], string(value))
+
+	return nil
+}
+
+// file streams a file part into a temporary file.
+func (state *upload) file(part *multipart.Part) error {
+	remaining, limitErr := state.limit(true)
+	maxFile := state.options.MaxFileBytes
+
+	var reader io.Reader = part
+
+	if maxFile > 0 && (remaining < 0 || maxFile < remaining) {
+		remaining = maxFile
+		limitErr = uploadTooLarge(ErrUploadFileTooLarge, maxFile).
+			With("field", part.FormName()).
+			With("filename", part.FileName())
+	}
+
+	if remaining >= 0 {
+		reader = io.LimitReader(part, remaining+1)
+	}
+
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(reader, sniff)
+
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return NewProblem(err, http.StatusBadRequest)
+	}
+
+	sniff = sniff[:n]
+	media, _, _ := mime.ParseMediaType(http.DetectContentType(sniff))
+
+	if len(state.options.MediaTypes) > 0 && !matchesMedia(media, state.options.MediaTypes) {
+		return Problem{
+			Detail: fmt.Sprintf("The uploaded file must be one of: %s.", strings.Join(state.options.MediaTypes, ", ")),
+			Status: http.StatusUnsupportedMediaType,
+		}.
+			WithError(fmt.Errorf("%w: %q", ErrUploadUnsupportedMediaType, media)).
+			With("field", part.FormName()).
+			With("filename", part.FileName()).
+			With("media-type", media)
+	}
+
+	temporary, err := os.CreateTemp(state.options.Dir, "akumu-upload-*")
+
+	if err != nil {
+		return NewProblem(err, http.StatusInternalServerError)
+	}
+
+	defer temporary.Close()
+
+	file := File{
+		Field:     part.FormName(),
+		Filename:  part.FileName(),
+		MediaType: media,
+		Header:    part.Header,
+		path:      temporary.Name(),
+	}
+
+	// The file is tracked before writing to it so that
+	// it's also removed if the writing fails.
+	state.files[file.Field] = append(state.files[file.Field], file)
+	files := state.files[file.Field]
+
+	size, err := io.Copy(temporary, io.MultiReader(bytes.NewReader(sniff), reader))
+
+	if err != nil {
+		return NewProblem(err, http.StatusBadRequest)
+	}
+
+	if remaining >= 0 && size > remaining {
+		return limitErr
+	}
+
+	state.total += size
+	files[len(files)-1].Size = size
+
+	return nil
+}
+
+// remove removes all the temporary files.
+func (state *upload) remove() {
+	for _, files := range state.files {
+		for _, file := range files {
+			_ = file.Remove()
+		}
+	}
+}
+
+// uploadTooLarge returns the [Problem] that describes
+// a limit violation of the given bytes.
+func uploadTooLarge(err error, limit int64) Problem {
+	return Problem{
+		Detail: fmt.Sprintf("The upload must not be larger than %d bytes.", limit),
+		Status: http.StatusRequestEntityTooLarge,
+	}.
+		WithError(err).
+		With("limit", limit)
+}
+
+// uploadFiles sets the `file` tagged fields of the given
+// struct value using the uploaded files.
+func uploadFiles(value reflect.Value, files map[string][]File) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			uploadFiles(value.Field(i), files)
+			continue
+		}
+
+		name, ok := field.Tag.Lookup("file")
+
+		if !ok || len(files[name]) == 0 {
+			continue
+		}
+
+		switch field.Type {
+		case fileType:
+			value.Field(i).Set(reflect.ValueOf(files[name][0]))
+		case reflect.PointerTo(fileType):
+			file := files[name][0]
+			value.Field(i).Set(reflect.ValueOf(&file))
+		case reflect.SliceOf(fileType):
+			value.Field(i).Set(reflect.ValueOf(files[name]))
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package akumu_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+)
+
+type UploadTestPayload struct {
+	Title       string       `form:"title"`
+	Public      bool         `form:"public"`
+	Avatar      akumu.File   `file:"avatar"`
+	Attachments []akumu.File `file:"attachments"`
+	Missing     *akumu.File  `file:"missing"`
+}
+
+func uploadTestRequest(t *testing.T, files map[string][]string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	_ = writer.WriteField("title", "hello")
+	_ = writer.WriteField("public", "true")
+
+	for field, contents := range files {
+		for i, content := range contents {
+			part, err := writer.CreateFormFile(field, field+string(rune('a'+i))+".txt")
+
+			if err != nil {
+				t.Fatalf("failed to create form file: %v", err)
+			}
+
+			_, _ = part.Write([]byte(content))
+		}
+	}
+
+	_ = writer.Close()
+
+	request, err := http.NewRequest(http.MethodPost, "/", body)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return request
+}
+
+func TestUpload(t *testing.T) {
+	request := uploadTestRequest(t, map[string][]string{
+		"avatar":      {"avatar contents"},
+		"attachments": {"first", "second"},
+	})
+
+	payload, err := akumu.Upload[UploadTestPayload](request, akumu.UploadOptions{
+		MaxFileBytes: 64,
+		MaxBytes:     1024,
+		MediaTypes:   []string{"text/*"},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to upload: %v", err)
+	}
+
+	defer payload.Avatar.Remove()
+
+	for _, attachment := range payload.Attachments {
+		defer attachment.Remove()
+	}
+
+	if payload.Title != "hello" || !payload.Public {
+		t.Fatalf("unexpected form values: %+v", payload)
+	}
+
+	if payload.Missing != nil {
+		t.Fatalf("expected missing file to be nil")
+	}
+
+	if expected := "text/plain"; payload.Avatar.MediaType != expected {
+		t.Fatalf("expected media type %s but got %s", expected, payload.Avatar.MediaType)
+	}
+
+	if expected := int64(len("avatar contents")); payload.Avatar.Size != expected {
+		t.Fatalf("expected size %d but got %d", expected, payload.Avatar.Size)
+	}
+
+	file, err := payload.Avatar.Open()
+
+	if err != nil {
+		t.Fatalf("failed to open uploaded file: %v", err)
+	}
+
+	defer file.Close()
+
+	if contents, _ := io.ReadAll(file); string(contents) != "avatar contents" {
+		t.Fatalf("unexpected file contents: %s", contents)
+	}
+
+	if expected := 2; len(payload.Attachments) != expected {
+		t.Fatalf("expected %d attachments but got %d", expected, len(payload.Attachments))
+	}
+}
+
+func TestUploadFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string][]string
+		options akumu.UploadOptions
+		err     error
+		status  int
+	}{
+		{
+			"file too large",
+			map[string][]string{"avatar": {strings.Repeat("a", 65)}},
+			akumu.UploadOptions{MaxFileBytes: 64},
+			akumu.ErrUploadFileTooLarge,
+			http.StatusRequestEntityTooLarge,
+		},
+		{
+			"too large",
+			map[string][]string{"attachments": {strings.Repeat("a", 40), strings.Repeat("b", 40)}},
+			akumu.UploadOptions{MaxFileBytes: 64, MaxBytes: 64},
+			akumu.ErrUploadTooLarge,
+			http.StatusRequestEntityTooLarge,
+		},
+		{
+			"media type",
+			map[string][]string{"avatar": {"<html><body>hello</body></html>"}},
+			akumu.UploadOptions{MediaTypes: []string{"image/*"}},
+			akumu.ErrUploadUnsupportedMediaType,
+			http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := uploadTestRequest(t, test.files)
+
+			_, err := akumu.Upload[UploadTestPayload](request, test.options)
+
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v but got %v", test.err, err)
+			}
+
+			var problem akumu.Problem
+
+			if errors.As(err, &problem); problem.Status != test.status {
+				t.Fatalf("expected status %d but got %d", test.status, problem.Status)
+			}
+		})
+	}
+}
+
+func TestUploadNotMultipart(t *testing.T) {
+	request, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	if _, err := akumu.Upload[UploadTestPayload](request, akumu.UploadOptions{}); !errors.Is(err, akumu.ErrUploadNotMultipart) {
+		t.Fatalf("expected error %v but got %v", akumu.ErrUploadNotMultipart, err)
+	}
+}
+
+func TestUploadRemovesFilesOnceResponded(t *testing.T) {
+	request := uploadTestRequest(t, map[string][]string{"avatar": {"avatar contents"}})
+
+	var avatar akumu.File
+
+	handler := func(request *http.Request) error {
+		payload, err := akumu.Upload[UploadTestPayload](request, akumu.UploadOptions{})
+
+		if err != nil {
+			return err
+		}
+
+		avatar = payload.Avatar
+		file, err := avatar.Open()
+
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		return akumu.Response(http.StatusNoContent)
+	}
+
+	if response := akumu.Record(handler, request); response.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d but got %d", http.StatusNoContent, response.Code)
+	}
+
+	if _, err := avatar.Open(); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the temporary file to be removed but got %v", err)
+	}
+}
+
+func TestUploadValuesLimit(t *testing.T) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	_ = writer.WriteField("title", strings.Repeat("a", akumu.UploadMaxValueBytes+1))
+	_ = writer.Close()
+
+	request, err := http.NewRequest(http.MethodPost, "/", body)
+
+	if err != nil {
+		t.Fatalf("failed to create http request: %v", err)
+	}
+
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	_, err = akumu.Upload[UploadTestPayload](request, akumu.UploadOptions{
+		MaxBytes: 2 * akumu.UploadMaxValueBytes,
+	})
+
+	if !errors.Is(err, akumu.ErrUploadTooLarge) {
+		t.Fatalf("expected error %v but got %v", akumu.ErrUploadTooLarge, err)
+	}
+
+	var problem akumu.Problem
+
+	if errors.As(err, &problem); problem.Status != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status %d but got %d", http.StatusRequestEntityTooLarge, problem.Status)
+	}
+
+	if limit, _ := problem.Additional("limit"); limit != int64(akumu.UploadMaxValueBytes) {
+		t.Fatalf("expected limit %d but got %v", akumu.UploadMaxValueBytes, limit)
+	}
+}
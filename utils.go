@@ -1,8 +1,9 @@
 package akumu
 
 // stackTrace creates a stack trace of all the errors found
-// that have been either Joined or Wrapped using [errors.Join]
-// or [fmt.Errorf] with `%w` directive.
+// that have been Joined using [errors.Join] or [fmt.Errorf]
+// with multiple `%w` directives. Errors wrapping a single
+// error are kept as is, see errorTree to traverse them.
 func stackTrace(err error) []error {
 	result := make([]error, 0)
 
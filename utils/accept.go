@@ -1,131 +1,237 @@
 package utils
 
 import (
+	"maps"
 	"mime"
 	"net/http"
 	"slices"
-	"strconv"
 	"strings"
 )
 
-// acceptPair is a simple structure that
-// holds the media and quality of an accept value.
-type acceptPair struct {
-	media   string
-	quality float64
+// MediaRange is a single media range found in
+// the "Accept" header, as defined in RFC 9110.
+type MediaRange struct {
+
+	// Type is the top-level type of the media range,
+	// which may be the "*" wildcard.
+	Type string
+
+	// Subtype is the subtype of the media range,
+	// which may be the "*" wildcard.
+	Subtype string
+
+	// Parameters stores the media type parameters
+	// of the range, excluding the "q" weight.
+	Parameters map[string]string
+
+	// Quality is the weight of the media range,
+	// between 0 and 1. A quality of 0 means that
+	// the media range is not acceptable.
+	Quality float64
 }
 
 // Accept is a type designed to help working
 // with header values found in the "Accept" header.
 type Accept struct {
-	values []acceptPair
+	values []MediaRange
 }
 
 // ParseAccept creates a new [Accept] based on a
 // [http.Request] by parsing its headers.
+//
+// Malformed media ranges are ignored, and so
+// are malformed weights, that default to 1.
 func ParseAccept(request *http.Request) Accept {
 	accept := Accept{
-		values: make([]acceptPair, 0),
+		values: make([]MediaRange, 0),
 	}
 
-	for _, header := range request.Header.Values("Accept") {
-		for _, line := range strings.Split(header, ",") {
-			media, parameters, err := mime.ParseMediaType(line)
+	elements, _ := headerElements(request, "Accept")
 
-			if err != nil {
-				continue
-			}
+	for _, element := range elements {
+		media, parameters, err := mime.ParseMediaType(element)
 
-			quality := 1.0
+		if err != nil {
+			continue
+		}
 
-			if param, ok := parameters["q"]; ok {
-				if q, err := strconv.ParseFloat(param, 64); err == nil {
-					quality = q
-				}
-			}
+		kind, subtype, ok := strings.Cut(media, "/")
 
-			accept.values = append(accept.values, acceptPair{
-				media:   media,
-				quality: quality,
-			})
+		if !ok || (kind == "*" && subtype != "*") {
+			continue
 		}
+
+		quality := 1.0
+
+		if param, ok := parameters["q"]; ok {
+			quality = parseQuality(param)
+
+			delete(parameters, "q")
+		}
+
+		accept.values = append(accept.values, MediaRange{
+			Type:       kind,
+			Subtype:    subtype,
+			Parameters: parameters,
+			Quality:    quality,
+		})
 	}
 
 	return accept
 }
 
-// find looks for a given media in the accept header and
-// returns its [acceptPair] if found.
+// String returns the media type of the range,
+// without its parameters nor its weight.
+func (media MediaRange) String() string {
+	return media.Type + "/" + media.Subtype
+}
+
+// Specificity returns how specific the media range is. Ranges
+// with parameters are more specific than "type/subtype", which
+// is more specific than "type/*", which is more specific than "*/*".
+func (media MediaRange) Specificity() int {
+	switch {
+	case media.Type == "*":
+		return 0
+	case media.Subtype == "*":
+		return 1
+	}
+
+	return 2 + len(media.Parameters)
+}
+
+// Matches reports whether the media range matches the
+// given media type, which may contain parameters and
+// wildcards. All the parameters of the range must be
+// found in the media type for it to match.
+func (media MediaRange) Matches(value string) bool {
+	parsed, parameters, err := mime.ParseMediaType(value)
+
+	if err != nil {
+		return false
+	}
+
+	kind, subtype, _ := strings.Cut(parsed, "/")
+
+	if media.Type != "*" && kind != "*" && media.Type != kind {
+		return false
+	}
+
+	if media.Subtype != "*" && subtype != "*" && media.Subtype != subtype {
+		return false
+	}
+
+	for key, param := range media.Parameters {
+		if parameters[key] != param {
+			return false
+		}
+	}
+
+	return true
+}
+
+// find looks for the most specific media range that matches
+// the given media in the accept header and returns it.
 //
 // The second return value is true when is found, and false otherwise.
-func (accept Accept) find(media string) (acceptPair, bool) {
-	for _, pair := range accept.values {
-		if media == pair.media {
-			return pair, true
-		}
+func (accept Accept) find(media string) (MediaRange, bool) {
+	found := MediaRange{}
+	ok := false
 
-		// Test for wildcard in media type
-		if strings.Contains(media, "/*") {
-			// Compare only the first part.
-			if trimmed := strings.TrimSuffix(media, "/*"); strings.HasPrefix(pair.media, trimmed) {
-				return pair, true
-			}
+	for _, value := range accept.values {
+		if !value.Matches(media) {
+			continue
 		}
 
-		// Test for wildcard in accept media type
-		if strings.Contains(pair.media, "/*") {
-			// Compare only the first part.
-			if trimmed := strings.TrimSuffix(pair.media, "/*"); strings.HasPrefix(media, trimmed) {
-				return pair, true
-			}
+		if !ok || value.Specificity() > found.Specificity() {
+			found = value
+			ok = true
 		}
 	}
 
-	return acceptPair{}, false
+	return found, ok
 }
 
-// Accepts reports whether the given media
-// is found in the accept headers.
+// Accepts reports whether the given media is accepted by
+// the accept headers, that is, if the most specific media
+// range that matches it does not have a quality of 0.
 func (accept Accept) Accepts(media string) bool {
-	_, found := accept.find(media)
-
-	return found
+	return accept.Quality(media) > 0
 }
 
-// Quality returns the quality of the given media
-// found in the accept headers.
+// Quality returns the quality of the given media found in
+// the accept headers, as given by the most specific media
+// range that matches it.
 //
 // Returns 0 if not found.
 func (accept Accept) Quality(media string) float64 {
-	if pair, found := accept.find(media); found {
-		return pair.quality
+	if value, found := accept.find(media); found {
+		return value.Quality
 	}
 
 	return 0
 }
 
-// Order creates an ordered slice that contains the
-// actual acceptance order based on the accept quality.
-func (accept Accept) Order() []string {
-	values := slices.Clone(accept.values)
+// Ranges returns the acceptable media ranges, ordered by
+// quality and then by specificity. Ranges with the same
+// quality and specificity keep the order of the header.
+//
+// Media ranges with a quality of 0 are not included.
+func (accept Accept) Ranges() []MediaRange {
+	values := make([]MediaRange, 0, len(accept.values))
+
+	for _, value := range accept.values {
+		if value.Quality > 0 {
+			value.Parameters = maps.Clone(value.Parameters)
+			values = append(values, value)
+		}
+	}
 
-	slices.SortFunc(values, func(a, b acceptPair) int {
-		if a.quality > b.quality {
+	slices.SortStableFunc(values, func(a, b MediaRange) int {
+		if a.Quality > b.Quality {
 			return -1
 		}
 
-		if a.quality < b.quality {
+		if a.Quality < b.Quality {
 			return 1
 		}
 
-		return 0
+		return b.Specificity() - a.Specificity()
 	})
 
-	keys := make([]string, len(accept.values))
+	return values
+}
+
+// Order creates an ordered slice that contains the
+// actual acceptance order based on the accept quality
+// and specificity. See [Accept.Ranges] for more details.
+func (accept Accept) Order() []string {
+	values := accept.Ranges()
+	keys := make([]string, len(values))
 
-	for i, pair := range values {
-		keys[i] = pair.media
+	for i, value := range values {
+		keys[i] = value.String()
 	}
 
 	return keys
 }
+
+// Best returns the offer that is best accepted, that is, the
+// one with the highest quality. Offers with the same quality
+// keep the given order, meaning that offers should be given in
+// the order preferred by the server.
+//
+// If there's no accept header, all offers are acceptable and
+// the first one is returned. An empty string is returned
+// when none of the offers is acceptable.
+func (accept Accept) Best(offers ...string) string {
+	if len(accept.values) == 0 {
+		if len(offers) > 0 {
+			return offers[0]
+		}
+
+		return ""
+	}
+
+	return bestOffer(offers, accept.Quality)
+}
@@ -2,6 +2,7 @@ package utils_test
 
 import (
 	"net/http"
+	"slices"
 	"testing"
 
 	"github.com/studiolambda/akumu/utils"
@@ -100,3 +101,131 @@ func TestAcceptOrder(t *testing.T) {
 		t.Fatalf("failed order element: %s, expected %s", order[4], expected)
 	}
 }
+
+func TestAcceptWildcards(t *testing.T) {
+	request, err := http.NewRequest("GET", "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create request: %s", err)
+	}
+
+	request.Header.Add("Accept", "text/*;q=0.5, */*;q=0.1")
+
+	accept := utils.ParseAccept(request)
+
+	if expected := 0.5; accept.Quality("text/html") != expected {
+		t.Fatalf("failed quality: %f, expected %f", accept.Quality("text/html"), expected)
+	}
+
+	if expected := 0.1; accept.Quality("textual/foo") != expected {
+		t.Fatalf("failed quality: %f, expected %f", accept.Quality("textual/foo"), expected)
+	}
+
+	if expected := "image/png"; !accept.Accepts(expected) {
+		t.Fatalf("failed to accept media type: %s", expected)
+	}
+}
+
+func TestAcceptRejection(t *testing.T) {
+	request, err := http.NewRequest("GET", "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create request: %s", err)
+	}
+
+	request.Header.Add("Accept", "text/*, text/plain;q=0")
+
+	accept := utils.ParseAccept(request)
+
+	if expected := "text/plain"; accept.Accepts(expected) {
+		t.Fatalf("failed to not accept media type: %s", expected)
+	}
+
+	if expected := "text/html"; !accept.Accepts(expected) {
+		t.Fatalf("failed to accept media type: %s", expected)
+	}
+
+	if expected := []string{"text/*"}; !slices.Equal(accept.Order(), expected) {
+		t.Fatalf("failed order: %v, expected %v", accept.Order(), expected)
+	}
+}
+
+func TestAcceptSpecificity(t *testing.T) {
+	request, err := http.NewRequest("GET", "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create request: %s", err)
+	}
+
+	request.Header.Add("Accept", "*/*, text/*, text/html, text/html;level=1;q=1")
+
+	accept := utils.ParseAccept(request)
+	ranges := accept.Ranges()
+
+	if expected := 4; len(ranges) != expected {
+		t.Fatalf("failed ranges len: %d, expected %d", len(ranges), expected)
+	}
+
+	if expected := "1"; ranges[0].Parameters["level"] != expected {
+		t.Fatalf("failed level parameter: %s, expected %s", ranges[0].Parameters["level"], expected)
+	}
+
+	expected := []string{"text/html", "text/html", "text/*", "*/*"}
+
+	if !slices.Equal(accept.Order(), expected) {
+		t.Fatalf("failed order: %v, expected %v", accept.Order(), expected)
+	}
+}
+
+func TestAcceptParameters(t *testing.T) {
+	request, err := http.NewRequest("GET", "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create request: %s", err)
+	}
+
+	request.Header.Add("Accept", "text/html;level=1, text/html;q=0.7")
+
+	accept := utils.ParseAccept(request)
+
+	if expected := 1.0; accept.Quality("text/html;level=1") != expected {
+		t.Fatalf("failed quality: %f, expected %f", accept.Quality("text/html;level=1"), expected)
+	}
+
+	if expected := 0.7; accept.Quality("text/html;level=2") != expected {
+		t.Fatalf("failed quality: %f, expected %f", accept.Quality("text/html;level=2"), expected)
+	}
+}
+
+func TestAcceptBest(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		offers []string
+		best   string
+	}{
+		{"no header", "", []string{"application/json", "application/xml"}, "application/json"},
+		{"quality", "application/json;q=0.5, application/xml", []string{"application/json", "application/xml"}, "application/xml"},
+		{"server preference", "application/*", []string{"application/xml", "application/json"}, "application/xml"},
+		{"rejected", "*/*, application/json;q=0", []string{"application/json", "text/plain"}, "text/plain"},
+		{"none", "text/html", []string{"application/json"}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, err := http.NewRequest("GET", "/", nil)
+
+			if err != nil {
+				t.Fatalf("failed to create request: %s", err)
+			}
+
+			if test.header != "" {
+				request.Header.Add("Accept", test.header)
+			}
+
+			if best := utils.ParseAccept(request).Best(test.offers...); best != test.best {
+				t.Fatalf("failed best: %s, expected %s", best, test.best)
+			}
+		})
+	}
+}
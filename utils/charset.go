@@ -0,0 +1,68 @@
+package utils
+
+import "net/http"
+
+// AcceptCharset is a type designed to help working with
+// header values found in the "Accept-Charset" header.
+type AcceptCharset struct {
+	values  []weight
+	present bool
+}
+
+// ParseAcceptCharset creates a new [AcceptCharset] based
+// on a [http.Request] by parsing its headers.
+func ParseAcceptCharset(request *http.Request) AcceptCharset {
+	values, present := parseWeights(request, "Accept-Charset")
+
+	return AcceptCharset{
+		values:  values,
+		present: present,
+	}
+}
+
+// Quality returns the quality of the given charset,
+// following the semantics of RFC 9110 section 12.5.2:
+//   - Any charset is acceptable when there's no header.
+//   - A charset that is listed uses its own quality.
+//   - A charset that is not listed uses the quality of "*", if listed.
+//
+// Charsets are compared case-insensitively.
+// Returns 0 if not acceptable.
+func (accept AcceptCharset) Quality(charset string) float64 {
+	if !accept.present {
+		return 1
+	}
+
+	if value, found := findWeight(accept.values, charset); found {
+		return value.quality
+	}
+
+	if value, found := findWeight(accept.values, "*"); found {
+		return value.quality
+	}
+
+	return 0
+}
+
+// Accepts reports whether the given charset is accepted.
+func (accept AcceptCharset) Accepts(charset string) bool {
+	return accept.Quality(charset) > 0
+}
+
+// Order creates an ordered slice that contains the charsets
+// based on their quality. Charsets with the same quality keep
+// the order of the header, and those with a quality of 0 are
+// not included.
+func (accept AcceptCharset) Order() []string {
+	return orderWeights(accept.values)
+}
+
+// Best returns the charset offer that is best accepted, that is,
+// the one with the highest quality. Offers with the same quality
+// keep the given order, meaning that offers should be given in
+// the order preferred by the server.
+//
+// An empty string is returned when none of the offers is acceptable.
+func (accept AcceptCharset) Best(offers ...string) string {
+	return bestOffer(offers, accept.Quality)
+}
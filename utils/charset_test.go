@@ -0,0 +1,55 @@
+package utils_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/studiolambda/akumu/utils"
+)
+
+func TestAcceptCharset(t *testing.T) {
+	request, err := http.NewRequest("GET", "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create request: %s", err)
+	}
+
+	request.Header.Add("Accept-Charset", "iso-8859-5, UTF-8;q=0.8, *;q=0.1, us-ascii;q=0")
+
+	accept := utils.ParseAcceptCharset(request)
+
+	if expected := 0.8; accept.Quality("utf-8") != expected {
+		t.Fatalf("failed quality: %f, expected %f", accept.Quality("utf-8"), expected)
+	}
+
+	if expected := 0.1; accept.Quality("utf-16") != expected {
+		t.Fatalf("failed quality: %f, expected %f", accept.Quality("utf-16"), expected)
+	}
+
+	if expected := "us-ascii"; accept.Accepts(expected) {
+		t.Fatalf("failed to not accept charset: %s", expected)
+	}
+
+	if expected := "utf-8"; accept.Best("us-ascii", "utf-8") != expected {
+		t.Fatalf("failed best: %s, expected %s", accept.Best("us-ascii", "utf-8"), expected)
+	}
+
+	if expected := []string{"iso-8859-5", "utf-8", "*"}; !slices.Equal(accept.Order(), expected) {
+		t.Fatalf("failed order: %v, expected %v", accept.Order(), expected)
+	}
+}
+
+func TestAcceptCharsetWithoutHeader(t *testing.T) {
+	request, err := http.NewRequest("GET", "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create request: %s", err)
+	}
+
+	accept := utils.ParseAcceptCharset(request)
+
+	if expected := "utf-8"; accept.Best("utf-8", "us-ascii") != expected {
+		t.Fatalf("failed best: %s, expected %s", accept.Best("utf-8", "us-ascii"), expected)
+	}
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AcceptEncoding is a type designed to help working with
+// header values found in the "Accept-Encoding" header.
+type AcceptEncoding struct {
+	values  []weight
+	present bool
+}
+
+// IdentityQuality is the quality given to the "identity" content
+// coding when the "Accept-Encoding" header neither lists nor rejects
+// it, making it acceptable but less preferred than any listed coding.
+const IdentityQuality = 0.001
+
+// ParseAcceptEncoding creates a new [AcceptEncoding] based
+// on a [http.Request] by parsing its headers.
+//
+// The "x-gzip" and "x-compress" codings are
+// treated as "gzip" and "compress".
+func ParseAcceptEncoding(request *http.Request) AcceptEncoding {
+	values, present := parseWeights(request, "Accept-Encoding")
+
+	for i, value := range values {
+		values[i].value = normalizeEncoding(value.value)
+	}
+
+	return AcceptEncoding{
+		values:  values,
+		present: present,
+	}
+}
+
+// normalizeEncoding returns the coding that is equivalent to
+// the given one, lowercased. Only "x-gzip" and "x-compress" are
+// equivalent to other codings, as defined in RFC 9110 section 8.4.1.
+func normalizeEncoding(coding string) string {
+	coding = strings.ToLower(coding)
+
+	switch coding {
+	case "x-gzip":
+		return "gzip"
+	case "x-compress":
+		return "compress"
+	}
+
+	return coding
+}
+
+// Quality returns the quality of the given content coding,
+// following the semantics of RFC 9110 section 12.5.3:
+//   - Any coding is acceptable when there's no header.
+//   - A coding that is listed uses its own quality.
+//   - A coding that is not listed uses the quality of "*", if listed.
+//   - The "identity" coding is acceptable unless it's rejected,
+//     either by itself or by "*", and uses [IdentityQuality].
+//
+// Returns 0 if not acceptable.
+func (accept AcceptEncoding) Quality(coding string) float64 {
+	if !accept.present {
+		return 1
+	}
+
+	coding = normalizeEncoding(coding)
+
+	if value, found := findWeight(accept.values, coding); found {
+		return value.quality
+	}
+
+	if value, found := findWeight(accept.values, "*"); found {
+		return value.quality
+	}
+
+	if coding == "identity" {
+		return IdentityQuality
+	}
+
+	return 0
+}
+
+// Accepts reports whether the given content coding is accepted.
+func (accept AcceptEncoding) Accepts(coding string) bool {
+	return accept.Quality(coding) > 0
+}
+
+// Order creates an ordered slice that contains the content
+// codings based on their quality. Codings with the same quality
+// keep the order of the header, and those with a quality of 0
+// are not included.
+func (accept AcceptEncoding) Order() []string {
+	return orderWeights(accept.values)
+}
+
+// Best returns the content coding offer that is best accepted, that
+// is, the one with the highest quality. Offers with the same quality
+// keep the given order, meaning that offers should be given in the
+// order preferred by the server.
+//
+// An empty string is returned when none of the offers is acceptable,
+// so including "identity" in the offers is recommended.
+func (accept AcceptEncoding) Best(offers ...string) string {
+	return bestOffer(offers, accept.Quality)
+}
@@ -0,0 +1,72 @@
+package utils_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/studiolambda/akumu/utils"
+)
+
+func TestAcceptEncodingQuality(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  []string
+		coding  string
+		quality float64
+	}{
+		{"no header", nil, "br", 1},
+		{"listed", []string{"gzip;q=0.5, br"}, "gzip", 0.5},
+		{"legacy", []string{"x-gzip;q=0.5"}, "GZIP", 0.5},
+		{"legacy compress", []string{"x-compress;q=0.5"}, "compress", 0.5},
+		{"unknown legacy", []string{"x-br;q=0.5"}, "br", 0},
+		{"custom", []string{"x-custom;q=0.5"}, "x-custom", 0.5},
+		{"not listed", []string{"gzip"}, "br", 0},
+		{"wildcard", []string{"gzip, *;q=0.2"}, "br", 0.2},
+		{"identity", []string{"gzip"}, "identity", utils.IdentityQuality},
+		{"empty", []string{""}, "identity", utils.IdentityQuality},
+		{"identity rejected", []string{"gzip, identity;q=0"}, "identity", 0},
+		{"wildcard rejected", []string{"gzip, *;q=0"}, "identity", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, err := http.NewRequest("GET", "/", nil)
+
+			if err != nil {
+				t.Fatalf("failed to create request: %s", err)
+			}
+
+			for _, header := range test.header {
+				request.Header.Add("Accept-Encoding", header)
+			}
+
+			if quality := utils.ParseAcceptEncoding(request).Quality(test.coding); quality != test.quality {
+				t.Fatalf("failed quality: %f, expected %f", quality, test.quality)
+			}
+		})
+	}
+}
+
+func TestAcceptEncodingBest(t *testing.T) {
+	request, err := http.NewRequest("GET", "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create request: %s", err)
+	}
+
+	request.Header.Add("Accept-Encoding", "gzip;q=0.8, deflate, br;q=0")
+
+	accept := utils.ParseAcceptEncoding(request)
+
+	if expected := "gzip"; accept.Best("br", "gzip", "identity") != expected {
+		t.Fatalf("failed best: %s, expected %s", accept.Best("br", "gzip", "identity"), expected)
+	}
+
+	if expected := "identity"; accept.Best("br", "identity") != expected {
+		t.Fatalf("failed best: %s, expected %s", accept.Best("br", "identity"), expected)
+	}
+
+	if expected := 2; len(accept.Order()) != expected {
+		t.Fatalf("failed order len: %d, expected %d", len(accept.Order()), expected)
+	}
+}
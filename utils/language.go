@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AcceptLanguage is a type designed to help working with
+// header values found in the "Accept-Language" header.
+//
+// Language ranges are matched against language tags
+// as defined in RFC 4647.
+type AcceptLanguage struct {
+	values  []weight
+	present bool
+}
+
+// ParseAcceptLanguage creates a new [AcceptLanguage] based
+// on a [http.Request] by parsing its headers.
+func ParseAcceptLanguage(request *http.Request) AcceptLanguage {
+	values, present := parseWeights(request, "Accept-Language")
+
+	return AcceptLanguage{
+		values:  values,
+		present: present,
+	}
+}
+
+// matchBasic reports whether the language range matches the
+// language tag using the basic filtering of RFC 4647 section 3.3.1.
+func matchBasic(language string, tag string) bool {
+	if language == "*" {
+		return true
+	}
+
+	tag = strings.ToLower(tag)
+
+	return tag == language || strings.HasPrefix(tag, language+"-")
+}
+
+// matchExtended reports whether the language range matches the
+// language tag using the extended filtering of RFC 4647 section 3.3.2.
+func matchExtended(language string, tag string) bool {
+	ranges := strings.Split(language, "-")
+	tags := strings.Split(strings.ToLower(tag), "-")
+
+	if ranges[0] != "*" && ranges[0] != tags[0] {
+		return false
+	}
+
+	ranges, tags = ranges[1:], tags[1:]
+
+	for len(ranges) > 0 {
+		switch {
+		case ranges[0] == "*":
+			ranges = ranges[1:]
+		case len(tags) == 0:
+			return false
+		case ranges[0] == tags[0]:
+			ranges, tags = ranges[1:], tags[1:]
+		case len(tags[0]) == 1:
+			return false
+		default:
+			tags = tags[1:]
+		}
+	}
+
+	return true
+}
+
+// find looks for the longest language range that
+// matches the given tag using basic filtering.
+//
+// The second return value is true when is found, and false otherwise.
+func (accept AcceptLanguage) find(tag string) (weight, bool) {
+	found := weight{}
+	ok := false
+
+	for _, value := range accept.values {
+		if !matchBasic(value.value, tag) {
+			continue
+		}
+
+		// The "*" wildcard is the least specific range, even
+		// if it's shorter than any other language range.
+		if !ok || found.value == "*" || (value.value != "*" && len(value.value) > len(found.value)) {
+			found = value
+			ok = true
+		}
+	}
+
+	return found, ok
+}
+
+// Quality returns the quality of the given language tag, as
+// given by the most specific language range that matches it
+// using basic filtering.
+//
+// Returns 1 if there's no header, as any language is
+// acceptable, and 0 if not found.
+func (accept AcceptLanguage) Quality(tag string) float64 {
+	if !accept.present {
+		return 1
+	}
+
+	if value, found := accept.find(tag); found {
+		return value.quality
+	}
+
+	return 0
+}
+
+// Accepts reports whether the given language tag is accepted.
+func (accept AcceptLanguage) Accepts(tag string) bool {
+	return accept.Quality(tag) > 0
+}
+
+// rejects reports whether the most specific language range
+// that matches the given tag has a quality of 0.
+func (accept AcceptLanguage) rejects(tag string) bool {
+	value, found := accept.find(tag)
+
+	return found && value.quality <= 0
+}
+
+// Order creates an ordered slice that contains the language
+// ranges based on their quality. Language ranges with the same
+// quality keep the order of the header, and those with a quality
+// of 0 are not included.
+func (accept AcceptLanguage) Order() []string {
+	return orderWeights(accept.values)
+}
+
+// filter returns the tags that match the acceptable language
+// ranges, in the order of the language ranges.
+func (accept AcceptLanguage) filter(tags []string, match func(language string, tag string) bool) []string {
+	if !accept.present {
+		return tags
+	}
+
+	filtered := make([]string, 0)
+
+	for _, language := range accept.Order() {
+		for _, tag := range tags {
+			if !match(language, tag) || accept.rejects(tag) {
+				continue
+			}
+
+			if !containsFold(filtered, tag) {
+				filtered = append(filtered, tag)
+			}
+		}
+	}
+
+	return filtered
+}
+
+// Filter returns the given language tags that match the language
+// ranges using the basic filtering of RFC 4647 section 3.3.1,
+// ordered by the quality of the language ranges.
+//
+// All the tags are returned if there's no header.
+func (accept AcceptLanguage) Filter(tags ...string) []string {
+	return accept.filter(tags, matchBasic)
+}
+
+// FilterExtended returns the given language tags that match the
+// language ranges using the extended filtering of RFC 4647 section 3.3.2,
+// ordered by the quality of the language ranges.
+//
+// For example, the "de-*-DE" language range matches "de-Latn-DE".
+//
+// All the tags are returned if there's no header.
+func (accept AcceptLanguage) FilterExtended(tags ...string) []string {
+	return accept.filter(tags, matchExtended)
+}
+
+// Lookup returns the language tag that best matches the language ranges
+// using the lookup of RFC 4647 section 3.4, that progressively truncates
+// each language range until a tag is found. For example, the "zh-Hant-CN"
+// language range looks for "zh-Hant-CN", then "zh-Hant" and then "zh".
+//
+// The fallback is returned if there's no match. If there's no header,
+// the first tag is returned instead, if any.
+func (accept AcceptLanguage) Lookup(tags []string, fallback string) string {
+	if !accept.present {
+		if len(tags) > 0 {
+			return tags[0]
+		}
+
+		return fallback
+	}
+
+	for _, language := range accept.Order() {
+		if language == "*" {
+			continue
+		}
+
+		for language != "" {
+			for _, tag := range tags {
+				if strings.EqualFold(tag, language) && !accept.rejects(tag) {
+					return tag
+				}
+			}
+
+			index := strings.LastIndex(language, "-")
+
+			if index < 0 {
+				break
+			}
+
+			language = language[:index]
+
+			// Singletons, such as "x" in "en-x-private",
+			// are also removed when truncating.
+			if index := strings.LastIndex(language, "-"); index >= 0 && len(language)-index == 2 {
+				language = language[:index]
+			}
+		}
+	}
+
+	return fallback
+}
+
+// containsFold reports whether the values
+// contain the given value, ignoring its case.
+func containsFold(values []string, value string) bool {
+	for _, current := range values {
+		if strings.EqualFold(current, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package utils_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/studiolambda/akumu/utils"
+)
+
+func languageTestRequest(t *testing.T, header string) *http.Request {
+	request, err := http.NewRequest("GET", "/", nil)
+
+	if err != nil {
+		t.Fatalf("failed to create request: %s", err)
+	}
+
+	if header != "" {
+		request.Header.Add("Accept-Language", header)
+	}
+
+	return request
+}
+
+func TestAcceptLanguageQuality(t *testing.T) {
+	accept := utils.ParseAcceptLanguage(languageTestRequest(t, "en-US, en;q=0.8, *;q=0.1, fr;q=0"))
+
+	tests := map[string]float64{
+		"en-US":  1,
+		"en-us":  1,
+		"en-GB":  0.8,
+		"es":     0.1,
+		"fr-CA":  0,
+		"enx-US": 0.1,
+	}
+
+	for tag, expected := range tests {
+		if quality := accept.Quality(tag); quality != expected {
+			t.Fatalf("failed quality of %s: %f, expected %f", tag, quality, expected)
+		}
+	}
+
+	if expected := []string{"en-us", "en", "*"}; !slices.Equal(accept.Order(), expected) {
+		t.Fatalf("failed order: %v, expected %v", accept.Order(), expected)
+	}
+}
+
+func TestAcceptLanguageFilter(t *testing.T) {
+	accept := utils.ParseAcceptLanguage(languageTestRequest(t, "de-DE;q=0.5, en"))
+	tags := []string{"de", "de-DE", "de-Latn-DE", "en-GB", "fr"}
+
+	if expected := []string{"en-GB", "de-DE"}; !slices.Equal(accept.Filter(tags...), expected) {
+		t.Fatalf("failed filter: %v, expected %v", accept.Filter(tags...), expected)
+	}
+}
+
+func TestAcceptLanguageFilterExtended(t *testing.T) {
+	accept := utils.ParseAcceptLanguage(languageTestRequest(t, "de-*-DE"))
+	tags := []string{"de", "de-DE", "de-Latn-DE", "de-x-DE", "de-Deva", "en-DE"}
+
+	if expected := []string{"de-DE", "de-Latn-DE"}; !slices.Equal(accept.FilterExtended(tags...), expected) {
+		t.Fatalf("failed filter: %v, expected %v", accept.FilterExtended(tags...), expected)
+	}
+}
+
+func TestAcceptLanguageLookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		tags     []string
+		expected string
+	}{
+		{"no header", "", []string{"es", "en"}, "es"},
+		{"exact", "en-GB, es", []string{"es", "en-GB"}, "en-GB"},
+		{"truncated", "zh-Hant-CN-x-private1", []string{"en", "zh", "zh-Hant"}, "zh-Hant"},
+		{"quality", "fr;q=0.5, es", []string{"fr", "es"}, "es"},
+		{"rejected", "en-US, en;q=0", []string{"en"}, "ca"},
+		{"fallback", "ja", []string{"en", "es"}, "ca"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			accept := utils.ParseAcceptLanguage(languageTestRequest(t, test.header))
+
+			if tag := accept.Lookup(test.tags, "ca"); tag != test.expected {
+				t.Fatalf("failed lookup: %s, expected %s", tag, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// weight is a single value of a header that uses
+// quality values, such as "gzip;q=0.5", as defined
+// in RFC 9110.
+type weight struct {
+	value   string
+	quality float64
+}
+
+// headerElements returns all the comma separated elements of
+// the given header, as they may be split into multiple lines.
+//
+// The second return value reports whether the header is present.
+func headerElements(request *http.Request, name string) ([]string, bool) {
+	headers := request.Header.Values(name)
+	elements := make([]string, 0)
+
+	for _, header := range headers {
+		for _, element := range strings.Split(header, ",") {
+			if element = strings.TrimSpace(element); element != "" {
+				elements = append(elements, element)
+			}
+		}
+	}
+
+	return elements, len(headers) > 0
+}
+
+// parseQuality parses the given "q" parameter. Malformed
+// weights default to 1 and are always kept between 0 and 1.
+func parseQuality(param string) float64 {
+	quality, err := strconv.ParseFloat(strings.TrimSpace(param), 64)
+
+	if err != nil {
+		return 1
+	}
+
+	return min(max(quality, 0), 1)
+}
+
+// parseWeights parses the given header into its weights, lowercasing
+// the values as they're case-insensitive. Any parameter other than the
+// "q" weight is ignored.
+//
+// The second return value reports whether the header is present.
+func parseWeights(request *http.Request, name string) ([]weight, bool) {
+	elements, present := headerElements(request, name)
+	weights := make([]weight, 0, len(elements))
+
+	for _, element := range elements {
+		value, parameters, _ := strings.Cut(element, ";")
+		quality := 1.0
+
+		for _, parameter := range strings.Split(parameters, ";") {
+			key, param, _ := strings.Cut(parameter, "=")
+
+			if strings.EqualFold(strings.TrimSpace(key), "q") {
+				quality = parseQuality(param)
+			}
+		}
+
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			weights = append(weights, weight{
+				value:   value,
+				quality: quality,
+			})
+		}
+	}
+
+	return weights, present
+}
+
+// findWeight looks for the weight of the given value,
+// comparing them case-insensitively.
+//
+// The second return value is true when is found, and false otherwise.
+func findWeight(weights []weight, value string) (weight, bool) {
+	for _, current := range weights {
+		if strings.EqualFold(current.value, value) {
+			return current, true
+		}
+	}
+
+	return weight{}, false
+}
+
+// orderWeights returns the values ordered by quality. Values with the
+// same quality keep the order of the header, and those with a quality
+// of 0 are not included.
+func orderWeights(weights []weight) []string {
+	ordered := slices.DeleteFunc(slices.Clone(weights), func(current weight) bool {
+		return current.quality <= 0
+	})
+
+	slices.SortStableFunc(ordered, func(a, b weight) int {
+		if a.quality > b.quality {
+			return -1
+		}
+
+		if a.quality < b.quality {
+			return 1
+		}
+
+		return 0
+	})
+
+	values := make([]string, len(ordered))
+
+	for i, current := range ordered {
+		values[i] = current.value
+	}
+
+	return values
+}
+
+// bestOffer returns the offer with the highest quality, as given by
+// the quality function. Offers with the same quality keep the given
+// order, and an empty string is returned if none is acceptable.
+func bestOffer(offers []string, quality func(offer string) float64) string {
+	best := ""
+	highest := 0.0
+
+	for _, offer := range offers {
+		if q := quality(offer); q > highest {
+			best = offer
+			highest = q
+		}
+	}
+
+	return best
+}
@@ -19,3 +19,42 @@ func TestUtilsStackTrace(t *testing.T) {
 		t.Fatalf("expected len '%d' but got '%d'", expected, len(trace))
 	}
 }
+
+func TestUtilsErrorTree(t *testing.T) {
+	err := errors.Join(
+		errors.New("first"),
+		fmt.Errorf("load user: %w", fmt.Errorf("query: %w", errors.New("second"))),
+	)
+
+	tree := errorTree(err, 0)
+
+	if expected := 2; len(tree) != expected {
+		t.Fatalf("expected len '%d' but got '%d'", expected, len(tree))
+	}
+
+	if expected := "load user: query: second"; tree[1].Message != expected {
+		t.Fatalf("expected message '%s' but got '%s'", expected, tree[1].Message)
+	}
+
+	if expected := "*fmt.wrapError"; tree[1].Type != expected {
+		t.Fatalf("expected type '%s' but got '%s'", expected, tree[1].Type)
+	}
+
+	if expected := 1; len(tree[1].Causes) != expected {
+		t.Fatalf("expected len '%d' but got '%d'", expected, len(tree[1].Causes))
+	}
+
+	leaf := tree[1].Causes[0].Causes[0]
+
+	if expected := "second"; leaf.Message != expected {
+		t.Fatalf("expected message '%s' but got '%s'", expected, leaf.Message)
+	}
+
+	if expected := 2; leaf.Depth != expected {
+		t.Fatalf("expected depth '%d' but got '%d'", expected, leaf.Depth)
+	}
+
+	if expected := "*errors.errorString"; leaf.Type != expected {
+		t.Fatalf("expected type '%s' but got '%s'", expected, leaf.Type)
+	}
+}
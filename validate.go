@@ -0,0 +1,421 @@
+package akumu
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Validator is implemented by types that can validate
+// themselves, usually after being decoded from a request.
+//
+// It's used by [Validate] to run custom rules that cannot
+// be expressed using the `validate` struct tag. If the
+// returned error implements [Responder], it's returned
+// as-is by [Validate] instead of being reported as an
+// invalid parameter.
+type Validator interface {
+	Validate() error
+}
+
+var (
+	// ErrValidation determines that a value did
+	// not pass its validation rules.
+	ErrValidation = errors.New("validation failed")
+
+	// ErrValidationRule determines that a `validate`
+	// struct tag contains an invalid rule.
+	ErrValidationRule = errors.New("invalid validation rule")
+)
+
+// validation holds the state of a running [Validate] call.
+type validation struct {
+	params    []InvalidParam
+	errs      []error
+	responder error
+	err       error
+}
+
+// validationRule is a parsed rule of a `validate` struct tag.
+type validationRule struct {
+	name     string
+	argument string
+	bound    float64
+	options  []string
+}
+
+// validationStruct stores the parsed `validate` struct
+// tags of a struct type, indexed by field.
+type validationStruct struct {
+	fields [][]validationRule
+	err    error
+}
+
+// validationStructs caches the [validationStruct]
+// of the struct types, keyed by [reflect.Type].
+var validationStructs sync.Map
+
+// Validate validates the given value, usually a struct or a pointer
+// to a struct, using its `validate` struct tags and the [Validator]
+// interface, which is checked on the value and on any nested struct.
+//
+// The `validate` tag is a comma separated list of rules:
+//   - required: the value must not be the zero value.
+//   - min=n: strings must have at least n characters, slices and maps
+//     at least n items and numbers must be greater or equal than n.
+//   - max=n: same as min, but as the upper bound.
+//   - len=n: strings must have exactly n characters and slices and maps
+//     exactly n items.
+//   - email: the value must be a valid email address.
+//   - url: the value must be an absolute URL.
+//   - oneof=a b c: the value must be one of the space separated values.
+//
+// Nil pointers only fail the "required" rule, making them
+// useful to represent optional values.
+//
+// If the validation fails, a [Problem] with [http.StatusUnprocessableEntity]
+// is returned, describing every failure in its "invalid-params" extension
+// member as a list of [InvalidParam]. The [InvalidParam] pointer is a JSON
+// pointer built from the `json` tags, unless the field is bound from another
+// request location, such as the `query`, in which case its location is used.
+//
+// The `validate` tags are parsed once per type. If a tag contains an
+// unknown rule or an invalid argument, a [Problem] with
+// [http.StatusInternalServerError] wrapping [ErrValidationRule] is
+// returned instead.
+func Validate(value any) error {
+	original := reflect.ValueOf(value)
+
+	if !original.IsValid() {
+		return nil
+	}
+
+	// An addressable copy is needed so that [Validator]
+	// implemented by pointer receivers is also found.
+	addressable := reflect.New(original.Type()).Elem()
+	addressable.Set(original)
+
+	state := &validation{
+		params: make([]InvalidParam, 0),
+		errs:   make([]error, 0),
+	}
+
+	state.value(addressable, "")
+
+	if state.err != nil {
+		return NewProblem(state.err, http.StatusInternalServerError)
+	}
+
+	if state.responder != nil {
+		return state.responder
+	}
+
+	if len(state.params) == 0 {
+		return nil
+	}
+
+	return NewProblem(
+		errors.Join(append([]error{ErrValidation}, state.errs...)...),
+		http.StatusUnprocessableEntity,
+	).With("invalid-params", state.params)
+}
+
+// fail records a new invalid parameter.
+func (state *validation) fail(param InvalidParam) {
+	state.params = append(state.params, param)
+	state.errs = append(state.errs, fmt.Errorf("%s: %s", param.Name, param.Reason))
+}
+
+// value validates nested values of the given value, located
+// at the given JSON pointer.
+func (state *validation) value(value reflect.Value, pointer string) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		state.structure(value, pointer)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			state.value(value.Index(i), fmt.Sprintf("%s/%d", pointer, i))
+		}
+	}
+}
+
+// structure validates the fields of the given struct value and
+// then calls its [Validator], if implemented.
+func (state *validation) structure(value reflect.Value, pointer string) {
+	if value.Type() == timeType {
+		return
+	}
+
+	parsed := validationRules(value.Type())
+
+	if parsed.err != nil {
+		if state.err == nil {
+			state.err = parsed.err
+		}
+
+		return
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			state.structure(value.Field(i), pointer)
+			continue
+		}
+
+		param := validationParam(field, pointer)
+
+		if rules := parsed.fields[i]; len(rules) > 0 {
+			if reason, failed := validateField(value.Field(i), rules); failed {
+				param.Reason = reason
+				state.fail(param)
+
+				continue
+			}
+		}
+
+		if param.Pointer != "" {
+			state.value(value.Field(i), param.Pointer)
+		}
+	}
+
+	state.validator(value, pointer)
+}
+
+// validator calls the [Validator] of the given value, if implemented.
+func (state *validation) validator(value reflect.Value, pointer string) {
+	candidate := value.Interface()
+
+	if value.CanAddr() {
+		candidate = value.Addr().Interface()
+	}
+
+	validator, ok := candidate.(Validator)
+
+	if !ok {
+		return
+	}
+
+	err := validator.Validate()
+
+	if err == nil {
+		return
+	}
+
+	if _, ok := err.(Responder); ok {
+		if state.responder == nil {
+			state.responder = err
+		}
+
+		return
+	}
+
+	state.fail(InvalidParam{
+		Name:    pointer[strings.LastIndex(pointer, "/")+1:],
+		Pointer: pointer,
+		Reason:  err.Error(),
+	})
+}
+
+// validationParam returns the [InvalidParam] that describes the
+// given struct field, without its reason.
+func validationParam(field reflect.StructField, pointer string) InvalidParam {
+	for _, source := range bindSources {
+		if name, ok := field.Tag.Lookup(source); ok {
+			return InvalidParam{Name: name, In: source}
+		}
+	}
+
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+	if name == "-" {
+		return InvalidParam{Name: field.Name}
+	}
+
+	if name == "" {
+		name = field.Name
+	}
+
+	return InvalidParam{
+		Name:    name,
+		Pointer: pointer + "/" + escapePointer(name),
+	}
+}
+
+// escapePointer escapes the given JSON pointer token, as
+// defined in RFC 6901.
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// validationRules returns the parsed `validate` struct tags
+// of the given struct type, parsing and caching them on first use.
+func validationRules(typ reflect.Type) validationStruct {
+	if cached, ok := validationStructs.Load(typ); ok {
+		return cached.(validationStruct)
+	}
+
+	parsed := validationStruct{
+		fields: make([][]validationRule, typ.NumField()),
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, ok := field.Tag.Lookup("validate")
+
+		if !ok {
+			continue
+		}
+
+		rules, err := parseValidationTag(tag)
+
+		if err != nil {
+			parsed.err = fmt.Errorf("%w: %s.%s: %w", ErrValidationRule, typ, field.Name, err)
+
+			break
+		}
+
+		parsed.fields[i] = rules
+	}
+
+	cached, _ := validationStructs.LoadOrStore(typ, parsed)
+
+	return cached.(validationStruct)
+}
+
+// parseValidationTag parses the rules of the given `validate` tag,
+// failing if any rule is unknown or has an invalid argument.
+func parseValidationTag(tag string) ([]validationRule, error) {
+	rules := make([]validationRule, 0)
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, argument, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		parsed := validationRule{name: name, argument: argument}
+
+		switch name {
+		case "":
+			continue
+		case "required", "email", "url":
+		case "min", "max", "len":
+			bound, err := strconv.ParseFloat(argument, 64)
+
+			if err != nil {
+				return nil, fmt.Errorf("rule %q has an invalid argument %q", name, argument)
+			}
+
+			parsed.bound = bound
+		case "oneof":
+			parsed.options = strings.Fields(argument)
+
+			if len(parsed.options) == 0 {
+				return nil, fmt.Errorf("rule %q has no options", name)
+			}
+		default:
+			return nil, fmt.Errorf("unknown rule %q", name)
+		}
+
+		rules = append(rules, parsed)
+	}
+
+	return rules, nil
+}
+
+// validateField runs the given rules against the field's
+// value and returns the reason of the first failing rule.
+func validateField(value reflect.Value, rules []validationRule) (string, bool) {
+	if value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			required := slices.ContainsFunc(rules, func(rule validationRule) bool {
+				return rule.name == "required"
+			})
+
+			if required {
+				return "is required", true
+			}
+
+			return "", false
+		}
+
+		value = value.Elem()
+	}
+
+	for _, rule := range rules {
+		if reason, failed := validateRule(value, rule); failed {
+			return reason, true
+		}
+	}
+
+	return "", false
+}
+
+// validateRule runs a single rule against the given value.
+func validateRule(value reflect.Value, rule validationRule) (string, bool) {
+	switch rule.name {
+	case "required":
+		return "is required", value.IsZero()
+	case "min":
+		return validateBound(value, rule, "at least", func(a, b float64) bool { return a < b })
+	case "max":
+		return validateBound(value, rule, "at most", func(a, b float64) bool { return a > b })
+	case "len":
+		return validateBound(value, rule, "exactly", func(a, b float64) bool { return a != b })
+	case "email":
+		address, err := mail.ParseAddress(value.String())
+
+		return "must be a valid email address", err != nil || address.Address != value.String()
+	case "url":
+		parsed, err := url.ParseRequestURI(value.String())
+
+		return "must be a valid URL", err != nil || parsed.Scheme == "" || parsed.Host == ""
+	case "oneof":
+		reason := fmt.Sprintf("must be one of: %s", strings.Join(rule.options, ", "))
+
+		return reason, !slices.Contains(rule.options, fmt.Sprint(value.Interface()))
+	}
+
+	return "", false
+}
+
+// validateBound compares the size of the given value against the
+// rule's bound using the given function, that reports a failure.
+func validateBound(value reflect.Value, rule validationRule, relation string, fails func(size, bound float64) bool) (string, bool) {
+	bound, argument := rule.bound, rule.argument
+
+	switch value.Kind() {
+	case reflect.String:
+		size := float64(utf8.RuneCountInString(value.String()))
+
+		return fmt.Sprintf("must be %s %s characters long", relation, argument), fails(size, bound)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("must contain %s %s items", relation, argument), fails(float64(value.Len()), bound)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprintf("must be %s %s", relation, argument), fails(float64(value.Int()), bound)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf("must be %s %s", relation, argument), fails(float64(value.Uint()), bound)
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("must be %s %s", relation, argument), fails(value.Float(), bound)
+	}
+
+	return "", false
+}
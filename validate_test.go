@@ -0,0 +1,157 @@
+package akumu_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/studiolambda/akumu"
+)
+
+type ValidateTestAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type ValidateTestUser struct {
+	Name      string                `json:"name" validate:"required,min=2,max=8"`
+	Email     string                `json:"email" validate:"email"`
+	Website   *string               `json:"website,omitempty" validate:"url"`
+	Role      string                `json:"role" validate:"oneof=admin user"`
+	Age       int                   `json:"age" validate:"min=18"`
+	Tags      []string              `json:"tags" validate:"max=2"`
+	Page      int                   `json:"-" query:"page" validate:"min=1"`
+	Addresses []ValidateTestAddress `json:"addresses"`
+}
+
+func (user *ValidateTestUser) Validate() error {
+	if user.Name == "root" {
+		return errors.New("name is reserved")
+	}
+
+	return nil
+}
+
+func validUser() ValidateTestUser {
+	return ValidateTestUser{
+		Name:      "john",
+		Email:     "john@example.com",
+		Role:      "admin",
+		Age:       30,
+		Tags:      []string{"a"},
+		Page:      1,
+		Addresses: []ValidateTestAddress{{City: "Barcelona"}},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := akumu.Validate(validUser()); err != nil {
+		t.Fatalf("expected user to be valid but got: %v", err)
+	}
+}
+
+func TestValidateFailures(t *testing.T) {
+	website := "not a url"
+	user := validUser()
+	user.Name = "j"
+	user.Email = "john"
+	user.Website = &website
+	user.Role = "guest"
+	user.Age = 10
+	user.Tags = []string{"a", "b", "c"}
+	user.Page = 0
+	user.Addresses = append(user.Addresses, ValidateTestAddress{})
+
+	err := akumu.Validate(&user)
+
+	if !errors.Is(err, akumu.ErrValidation) {
+		t.Fatalf("expected error %v but got %v", akumu.ErrValidation, err)
+	}
+
+	var problem akumu.Problem
+
+	if !errors.As(err, &problem) {
+		t.Fatalf("expected error to be a problem")
+	}
+
+	if expected := http.StatusUnprocessableEntity; problem.Status != expected {
+		t.Fatalf("expected status %d but got %d", expected, problem.Status)
+	}
+
+	value, _ := problem.Additional("invalid-params")
+	params := value.([]akumu.InvalidParam)
+
+	expected := []akumu.InvalidParam{
+		{Name: "name", Pointer: "/name", Reason: "must be at least 2 characters long"},
+		{Name: "email", Pointer: "/email", Reason: "must be a valid email address"},
+		{Name: "website", Pointer: "/website", Reason: "must be a valid URL"},
+		{Name: "role", Pointer: "/role", Reason: "must be one of: admin, user"},
+		{Name: "age", Pointer: "/age", Reason: "must be at least 18"},
+		{Name: "tags", Pointer: "/tags", Reason: "must contain at most 2 items"},
+		{Name: "page", In: "query", Reason: "must be at least 1"},
+		{Name: "city", Pointer: "/addresses/1/city", Reason: "is required"},
+	}
+
+	if len(params) != len(expected) {
+		t.Fatalf("expected %d invalid params but got %d: %+v", len(expected), len(params), params)
+	}
+
+	for i := range expected {
+		if params[i] != expected[i] {
+			t.Fatalf("expected invalid param %+v but got %+v", expected[i], params[i])
+		}
+	}
+}
+
+func TestValidateValidator(t *testing.T) {
+	user := validUser()
+	user.Name = "root"
+
+	err := akumu.Validate(user)
+
+	var problem akumu.Problem
+
+	if !errors.As(err, &problem) {
+		t.Fatalf("expected error to be a problem")
+	}
+
+	value, _ := problem.Additional("invalid-params")
+
+	if params := value.([]akumu.InvalidParam); len(params) != 1 || params[0].Reason != "name is reserved" {
+		t.Fatalf("unexpected invalid params: %+v", params)
+	}
+}
+
+func TestValidateInvalidRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"unknown rule", struct {
+			Name string `validate:"required,unknown"`
+		}{}},
+		{"invalid argument", struct {
+			Age int `validate:"min=abc"`
+		}{}},
+		{"missing options", struct {
+			Role string `validate:"oneof="`
+		}{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for range 2 {
+				err := akumu.Validate(test.value)
+
+				if !errors.Is(err, akumu.ErrValidationRule) {
+					t.Fatalf("expected error %v but got %v", akumu.ErrValidationRule, err)
+				}
+
+				var problem akumu.Problem
+
+				if !errors.As(err, &problem) || problem.Status != http.StatusInternalServerError {
+					t.Fatalf("expected a %d problem but got %v", http.StatusInternalServerError, err)
+				}
+			}
+		})
+	}
+}